@@ -4,12 +4,17 @@
 
 // Package agent provides hooks programs can register to retrieve
 // diagnostics data by using gops.
+//
+// Commands, health checks, variables and log levels are registered by
+// name, before or after Listen. The names must be unique and without
+// spaces, the Register functions panicking otherwise.
 package agent
 
 import (
 	"bufio"
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -63,6 +68,46 @@ type Options struct {
 	// Addr is set to a fixed host:port.
 	// Optional.
 	ReuseSocketAddrAndPort bool
+
+	// FlightRecorder, if set, continuously records the execution trace
+	// and keeps the most recent part of it in memory, so that it can be
+	// retrieved with `gops trace --snapshot` after the fact. It requires
+	// Go 1.25 or later.
+	// Optional.
+	FlightRecorder *FlightRecorderOptions
+
+	// ProfileHistory, if set, periodically captures CPU and heap profiles
+	// into a bounded directory, to be listed and retrieved with
+	// `gops profiles-history` after the fact.
+	// Optional.
+	ProfileHistory *ProfileHistoryOptions
+
+	// Triggers, if set, automatically captures diagnostics when the
+	// process crosses one of the configured thresholds, to be retrieved
+	// with `gops captures` after the fact.
+	// Optional.
+	Triggers *TriggerOptions
+
+	// CrashOutput, if set, writes what the runtime prints when the
+	// process crashes, such as the traceback of an unrecovered panic, to
+	// a file in the config directory, to be printed with `gops crashes`
+	// after the process died. The file is removed by Close, and the files
+	// of the processes that are gone without crash output are removed
+	// when the agent starts, keeping the 20 most recent crashes. It
+	// requires Go 1.23 or later.
+	// Optional.
+	CrashOutput bool
+}
+
+// FlightRecorderOptions configures the execution trace flight recorder.
+type FlightRecorderOptions struct {
+	// MinAge is how far back the recorded trace should at least go.
+	// Defaults to a runtime-defined value on the order of seconds.
+	MinAge time.Duration
+
+	// MaxBytes bounds the size of the recorded trace and takes
+	// precedence over MinAge. Defaults to a runtime-defined value.
+	MaxBytes uint64
 }
 
 // Listen starts the gops agent on a host process. Once agent started, users
@@ -92,9 +137,16 @@ func Listen(opts Options) error {
 		lc.Control = setReuseAddrAndPortSockopts
 	}
 
+	if opts.FlightRecorder != nil {
+		if err := startFlightRecorder(*opts.FlightRecorder); err != nil {
+			return err
+		}
+	}
+
 	var err error
 	listener, err = lc.Listen(context.Background(), "tcp", addr)
 	if err != nil {
+		stopFlightRecorder()
 		return err
 	}
 
@@ -103,19 +155,27 @@ func Listen(opts Options) error {
 	if err != nil {
 		// ignore and work in remote mode only
 		if !errors.Is(err, syscall.EROFS) && !errors.Is(err, syscall.EPERM) {
+			closeListener()
+			stopFlightRecorder()
 			return err
 		}
 	}
 
+	if err := startBackground(opts); err != nil {
+		closeListener()
+		stopBackground()
+		return err
+	}
+
 	if opts.ShutdownCleanup {
 		gracefulShutdown()
 	}
+	enabledOptions = optionNames(opts)
 	go listen(listener)
 	return nil
 }
 
 func listen(l net.Listener) {
-	buf := make([]byte, 1)
 	for {
 		fd, err := l.Accept()
 		if err != nil {
@@ -127,29 +187,77 @@ func listen(l net.Listener) {
 			}
 			continue
 		}
-		if _, err := fd.Read(buf); err != nil {
-			fmt.Fprintf(os.Stderr, "gops: %v\n", err)
-			continue
+		// Connections are served concurrently so that long-running
+		// commands, such as streams, don't block the others.
+		go serve(fd)
+	}
+}
+
+func serve(fd net.Conn) {
+	defer fd.Close()
+	buf := make([]byte, 1)
+	if _, err := fd.Read(buf); err != nil {
+		fmt.Fprintf(os.Stderr, "gops: %v\n", err)
+		return
+	}
+	if err := handle(fd, buf); err != nil {
+		fmt.Fprintf(os.Stderr, "gops: %v\n", err)
+	}
+}
+
+// closed returns a channel that is closed once the client closes the
+// connection read by r. It is meant for streams, to which the client
+// doesn't send anything after the command.
+func closed(r io.Reader) <-chan struct{} {
+	c := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, r)
+		close(c)
+	}()
+	return c
+}
+
+// startBackground starts the background captures enabled in opts.
+func startBackground(opts Options) error {
+	if opts.ProfileHistory == nil && opts.Triggers == nil && !opts.CrashOutput {
+		return nil
+	}
+	gopsdir, err := configDir(opts)
+	if err != nil {
+		return err
+	}
+	if opts.ProfileHistory != nil {
+		if err := startProfileHistory(*opts.ProfileHistory, gopsdir); err != nil {
+			return err
 		}
-		if err := handle(fd, buf); err != nil {
-			fmt.Fprintf(os.Stderr, "gops: %v\n", err)
-			continue
+	}
+	if opts.Triggers != nil {
+		if err := startTriggers(*opts.Triggers, gopsdir); err != nil {
+			return err
 		}
-		fd.Close()
 	}
+	if opts.CrashOutput {
+		if err := startCrashOutput(gopsdir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func configDir(opts Options) (string, error) {
+	if opts.ConfigDir != "" {
+		return opts.ConfigDir, nil
+	}
+	return internal.ConfigDir()
 }
 
 func saveConfig(opts Options, port int) error {
-	gopsdir := opts.ConfigDir
-	if gopsdir == "" {
-		cfgDir, err := internal.ConfigDir()
-		if err != nil {
-			return err
-		}
-		gopsdir = cfgDir
+	gopsdir, err := configDir(opts)
+	if err != nil {
+		return err
 	}
 
-	err := os.MkdirAll(gopsdir, os.ModePerm)
+	err = os.MkdirAll(gopsdir, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -179,6 +287,13 @@ func Close() {
 	mu.Lock()
 	defer mu.Unlock()
 
+	closeListener()
+	stopBackground()
+	enabledOptions = nil
+}
+
+// closeListener closes the listener and removes the port file, if any.
+func closeListener() {
 	if portfile != "" {
 		os.Remove(portfile)
 		portfile = ""
@@ -189,6 +304,14 @@ func Close() {
 	}
 }
 
+// stopBackground stops the flight recorder and the background captures.
+func stopBackground() {
+	stopFlightRecorder()
+	stopProfileHistory()
+	stopTriggers()
+	stopCrashOutput()
+}
+
 func formatBytes(val uint64) string {
 	var i int
 	var target uint64
@@ -204,7 +327,48 @@ func formatBytes(val uint64) string {
 	return fmt.Sprintf("%d bytes", val)
 }
 
+func writeMemStats(w io.Writer, s *runtime.MemStats) {
+	fmt.Fprintf(w, "alloc: %v\n", formatBytes(s.Alloc))
+	fmt.Fprintf(w, "total-alloc: %v\n", formatBytes(s.TotalAlloc))
+	fmt.Fprintf(w, "sys: %v\n", formatBytes(s.Sys))
+	fmt.Fprintf(w, "lookups: %v\n", s.Lookups)
+	fmt.Fprintf(w, "mallocs: %v\n", s.Mallocs)
+	fmt.Fprintf(w, "frees: %v\n", s.Frees)
+	fmt.Fprintf(w, "heap-alloc: %v\n", formatBytes(s.HeapAlloc))
+	fmt.Fprintf(w, "heap-sys: %v\n", formatBytes(s.HeapSys))
+	fmt.Fprintf(w, "heap-idle: %v\n", formatBytes(s.HeapIdle))
+	fmt.Fprintf(w, "heap-in-use: %v\n", formatBytes(s.HeapInuse))
+	fmt.Fprintf(w, "heap-released: %v\n", formatBytes(s.HeapReleased))
+	fmt.Fprintf(w, "heap-objects: %v\n", s.HeapObjects)
+	fmt.Fprintf(w, "stack-in-use: %v\n", formatBytes(s.StackInuse))
+	fmt.Fprintf(w, "stack-sys: %v\n", formatBytes(s.StackSys))
+	fmt.Fprintf(w, "mspan-in-use: %v\n", formatBytes(s.MSpanInuse))
+	fmt.Fprintf(w, "mspan-sys: %v\n", formatBytes(s.MSpanSys))
+	fmt.Fprintf(w, "mcache-in-use: %v\n", formatBytes(s.MCacheInuse))
+	fmt.Fprintf(w, "mcache-sys: %v\n", formatBytes(s.MCacheSys))
+	fmt.Fprintf(w, "buck-hash-sys: %v\n", formatBytes(s.BuckHashSys))
+	fmt.Fprintf(w, "other-sys: %v\n", formatBytes(s.OtherSys))
+	fmt.Fprintf(w, "gc-sys: %v\n", formatBytes(s.GCSys))
+	fmt.Fprintf(w, "next-gc: when heap-alloc >= %v\n", formatBytes(s.NextGC))
+	lastGC := "-"
+	if s.LastGC != 0 {
+		lastGC = fmt.Sprint(time.Unix(0, int64(s.LastGC)))
+	}
+	fmt.Fprintf(w, "last-gc: %v\n", lastGC)
+	fmt.Fprintf(w, "gc-pause-total: %v\n", time.Duration(s.PauseTotalNs))
+	fmt.Fprintf(w, "gc-pause: %v\n", s.PauseNs[(s.NumGC+255)%256])
+	fmt.Fprintf(w, "gc-pause-end: %v\n", s.PauseEnd[(s.NumGC+255)%256])
+	fmt.Fprintf(w, "num-gc: %v\n", s.NumGC)
+	fmt.Fprintf(w, "num-forced-gc: %v\n", s.NumForcedGC)
+	fmt.Fprintf(w, "gc-cpu-fraction: %v\n", s.GCCPUFraction)
+	fmt.Fprintf(w, "enable-gc: %v\n", s.EnableGC)
+	fmt.Fprintf(w, "debug-gc: %v\n", s.DebugGC)
+}
+
 func handle(conn io.ReadWriter, msg []byte) error {
+	if fn, ok := replyHandlers[msg[0]]; ok {
+		return reply(conn, fn)
+	}
 	switch msg[0] {
 	case signal.StackTrace:
 		return pprof.Lookup("goroutine").WriteTo(conn, 2)
@@ -215,41 +379,7 @@ func handle(conn io.ReadWriter, msg []byte) error {
 	case signal.MemStats:
 		var s runtime.MemStats
 		runtime.ReadMemStats(&s)
-		fmt.Fprintf(conn, "alloc: %v\n", formatBytes(s.Alloc))
-		fmt.Fprintf(conn, "total-alloc: %v\n", formatBytes(s.TotalAlloc))
-		fmt.Fprintf(conn, "sys: %v\n", formatBytes(s.Sys))
-		fmt.Fprintf(conn, "lookups: %v\n", s.Lookups)
-		fmt.Fprintf(conn, "mallocs: %v\n", s.Mallocs)
-		fmt.Fprintf(conn, "frees: %v\n", s.Frees)
-		fmt.Fprintf(conn, "heap-alloc: %v\n", formatBytes(s.HeapAlloc))
-		fmt.Fprintf(conn, "heap-sys: %v\n", formatBytes(s.HeapSys))
-		fmt.Fprintf(conn, "heap-idle: %v\n", formatBytes(s.HeapIdle))
-		fmt.Fprintf(conn, "heap-in-use: %v\n", formatBytes(s.HeapInuse))
-		fmt.Fprintf(conn, "heap-released: %v\n", formatBytes(s.HeapReleased))
-		fmt.Fprintf(conn, "heap-objects: %v\n", s.HeapObjects)
-		fmt.Fprintf(conn, "stack-in-use: %v\n", formatBytes(s.StackInuse))
-		fmt.Fprintf(conn, "stack-sys: %v\n", formatBytes(s.StackSys))
-		fmt.Fprintf(conn, "mspan-in-use: %v\n", formatBytes(s.MSpanInuse))
-		fmt.Fprintf(conn, "mspan-sys: %v\n", formatBytes(s.MSpanSys))
-		fmt.Fprintf(conn, "mcache-in-use: %v\n", formatBytes(s.MCacheInuse))
-		fmt.Fprintf(conn, "mcache-sys: %v\n", formatBytes(s.MCacheSys))
-		fmt.Fprintf(conn, "buck-hash-sys: %v\n", formatBytes(s.BuckHashSys))
-		fmt.Fprintf(conn, "other-sys: %v\n", formatBytes(s.OtherSys))
-		fmt.Fprintf(conn, "gc-sys: %v\n", formatBytes(s.GCSys))
-		fmt.Fprintf(conn, "next-gc: when heap-alloc >= %v\n", formatBytes(s.NextGC))
-		lastGC := "-"
-		if s.LastGC != 0 {
-			lastGC = fmt.Sprint(time.Unix(0, int64(s.LastGC)))
-		}
-		fmt.Fprintf(conn, "last-gc: %v\n", lastGC)
-		fmt.Fprintf(conn, "gc-pause-total: %v\n", time.Duration(s.PauseTotalNs))
-		fmt.Fprintf(conn, "gc-pause: %v\n", s.PauseNs[(s.NumGC+255)%256])
-		fmt.Fprintf(conn, "gc-pause-end: %v\n", s.PauseEnd[(s.NumGC+255)%256])
-		fmt.Fprintf(conn, "num-gc: %v\n", s.NumGC)
-		fmt.Fprintf(conn, "num-forced-gc: %v\n", s.NumForcedGC)
-		fmt.Fprintf(conn, "gc-cpu-fraction: %v\n", s.GCCPUFraction)
-		fmt.Fprintf(conn, "enable-gc: %v\n", s.EnableGC)
-		fmt.Fprintf(conn, "debug-gc: %v\n", s.DebugGC)
+		writeMemStats(conn, &s)
 	case signal.Version:
 		fmt.Fprintf(conn, "%v\n", runtime.Version())
 	case signal.HeapProfile:
@@ -261,10 +391,7 @@ func handle(conn io.ReadWriter, msg []byte) error {
 		time.Sleep(30 * time.Second)
 		pprof.StopCPUProfile()
 	case signal.Stats:
-		fmt.Fprintf(conn, "goroutines: %v\n", runtime.NumGoroutine())
-		fmt.Fprintf(conn, "OS threads: %v\n", pprof.Lookup("threadcreate").Count())
-		fmt.Fprintf(conn, "GOMAXPROCS: %v\n", runtime.GOMAXPROCS(0))
-		fmt.Fprintf(conn, "num CPU: %v\n", runtime.NumCPU())
+		writeStats(conn)
 	case signal.BinaryDump:
 		path, err := os.Executable()
 		if err != nil {
@@ -293,3 +420,92 @@ func handle(conn io.ReadWriter, msg []byte) error {
 	}
 	return nil
 }
+
+// legacyCommands are the commands that don't report their status, which
+// all agents support.
+var legacyCommands = []byte{
+	signal.StackTrace,
+	signal.GC,
+	signal.MemStats,
+	signal.Version,
+	signal.HeapProfile,
+	signal.CPUProfile,
+	signal.Stats,
+	signal.BinaryDump,
+	signal.Trace,
+	signal.SetGCPercent,
+}
+
+// replyHandlers are the commands that report their status to the client.
+var replyHandlers map[byte]func(r io.Reader, w io.Writer) error
+
+func init() {
+	// Not initialized in the declaration, as capabilities depends on it.
+	replyHandlers = map[byte]func(r io.Reader, w io.Writer) error{
+		signal.FilteredStackTrace: filteredStackTrace,
+		signal.TraceSnapshot:      traceSnapshot,
+		signal.ListProfiles:       listProfiles,
+		signal.FetchProfile:       fetchProfile,
+		signal.ListCaptures:       listCaptures,
+		signal.FetchCapture:       fetchCapture,
+		signal.GCTrace:            gcTrace,
+		signal.GCStats:            gcStats,
+		signal.HeapDump:           heapDump,
+		signal.FileDescriptors:    fileDescriptors,
+		signal.Symbolize:          symbolize,
+		signal.Vars:               vars,
+		signal.SampleStacks:       sampleStacks,
+		signal.ListCommands:       listCommands,
+		signal.CallCommand:        callCommand,
+		signal.Capabilities:       capabilities,
+		signal.HealthCheck:        healthCheck,
+		signal.GetVars:            getVars,
+		signal.SetVar:             setVar,
+		signal.LogLevel:           logLevel,
+		signal.Logs:               logs,
+		signal.Requests:           requests,
+		signal.DBStats:            dbStats,
+		signal.Coverage:           coverageData,
+	}
+}
+
+func gcStats(_ io.Reader, w io.Writer) error {
+	// Percentiles of the pause history.
+	s := debug.GCStats{PauseQuantiles: make([]time.Duration, 101)}
+	debug.ReadGCStats(&s)
+	return json.NewEncoder(w).Encode(&s)
+}
+
+// reply runs fn for a command that reports its status to the client. The
+// output of fn is preceded by internal.StatusOK, unless fn fails before
+// writing anything, in which case the error is sent after
+// internal.StatusError instead.
+func reply(conn io.ReadWriter, fn func(r io.Reader, w io.Writer) error) error {
+	w := &replyWriter{w: conn}
+	err := fn(conn, w)
+	if w.started {
+		return err
+	}
+	if err != nil {
+		_, err = conn.Write(append([]byte{internal.StatusError}, err.Error()...))
+		return err
+	}
+	_, err = conn.Write([]byte{internal.StatusOK})
+	return err
+}
+
+// replyWriter writes internal.StatusOK before the first write.
+type replyWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (w *replyWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		if _, err := w.w.Write([]byte{internal.StatusOK}); err != nil {
+			return 0, err
+		}
+	}
+	return w.w.Write(p)
+}
@@ -6,6 +6,8 @@ package agent
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 )
 
@@ -87,3 +89,31 @@ func TestFormatBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatCounts(t *testing.T) {
+	got := formatCounts(map[string]int{"running": 1, "select": 3, "chan receive": 3, "IO wait": 2})
+	if want := "chan receive 3, select 3, IO wait 2, running 1"; got != want {
+		t.Errorf("formatCounts() = %q; want %q", got, want)
+	}
+}
+
+func TestListenBackgroundError(t *testing.T) {
+	dir := t.TempDir()
+	// The profile directory can't be created under a file.
+	notDir := filepath.Join(dir, "file")
+	if err := os.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := Listen(Options{ConfigDir: dir, ProfileHistory: &ProfileHistoryOptions{Dir: filepath.Join(notDir, "profiles")}})
+	if err == nil {
+		Close()
+		t.Fatal("Listen succeeded; want an error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, strconv.Itoa(os.Getpid()))); !os.IsNotExist(err) {
+		t.Errorf("port file left behind: %v", err)
+	}
+	if err := Listen(Options{ConfigDir: dir}); err != nil {
+		t.Fatalf("Listen after a failed Listen: %v", err)
+	}
+	Close()
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"github.com/google/gops/internal"
+)
+
+// enabledOptions are the names of the options the agent was started with.
+var enabledOptions []string
+
+func optionNames(opts Options) []string {
+	var names []string
+	if opts.FlightRecorder != nil {
+		names = append(names, "flight-recorder")
+	}
+	if opts.ProfileHistory != nil {
+		names = append(names, "profile-history")
+	}
+	if opts.Triggers != nil {
+		names = append(names, "triggers")
+	}
+	if opts.CrashOutput {
+		names = append(names, "crash-output")
+	}
+	if opts.ShutdownCleanup {
+		names = append(names, "shutdown-cleanup")
+	}
+	return names
+}
+
+// agentVersion returns the version of the gops module.
+func agentVersion() string {
+	const path = "github.com/google/gops"
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if bi.Main.Path == path {
+		return bi.Main.Version
+	}
+	for _, m := range bi.Deps {
+		if m.Path != path {
+			continue
+		}
+		if m.Replace != nil && m.Replace.Version != "" {
+			return m.Replace.Version
+		}
+		return m.Version
+	}
+	return "unknown"
+}
+
+func capabilities(_ io.Reader, w io.Writer) error {
+	mu.Lock()
+	opts := enabledOptions
+	mu.Unlock()
+	c := internal.Capabilities{
+		AgentVersion: agentVersion(),
+		GoVersion:    runtime.Version(),
+		Options:      opts,
+	}
+	for _, cmd := range legacyCommands {
+		c.Commands = append(c.Commands, int(cmd))
+	}
+	for cmd := range replyHandlers {
+		c.Commands = append(c.Commands, int(cmd))
+	}
+	sort.Ints(c.Commands)
+	return json.NewEncoder(w).Encode(c)
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupLimits returns the CPU quota, in CPUs, and the memory limit, in
+// bytes, of the cgroups of the process. Zero means unlimited.
+func cgroupLimits() (cpu float64, mem uint64, ok bool) {
+	b, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return 0, 0, false
+	}
+	cpu, mem = readCgroupLimits("/sys/fs/cgroup", b)
+	return cpu, mem, true
+}
+
+// readCgroupLimits reads the limits of the cgroups listed in the content of
+// /proc/self/cgroup from the hierarchies mounted under root. The limits are
+// the lowest ones of the cgroups and their ancestors.
+func readCgroupLimits(root string, procCgroup []byte) (cpu float64, mem uint64) {
+	var v1CPU, v1Mem bool
+	for _, line := range strings.Split(strings.TrimSpace(string(procCgroup)), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		controllers, p := parts[1], parts[2]
+		if controllers == "" {
+			// cgroup v2, unless overridden by v1 controllers.
+			if !v1CPU {
+				cpu = minCPU(cpu, walkCgroup(root, p, readCPUMax))
+			}
+			if !v1Mem {
+				mem = minMem(mem, walkCgroup(root, p, readMemoryMax))
+			}
+			continue
+		}
+		for _, c := range strings.Split(controllers, ",") {
+			dir := filepath.Join(root, controllers)
+			if _, err := os.Stat(dir); err != nil {
+				dir = filepath.Join(root, c)
+			}
+			switch c {
+			case "cpu":
+				v1CPU = true
+				cpu = walkCgroup(dir, p, readCFSQuota)
+			case "memory":
+				v1Mem = true
+				mem = walkCgroup(dir, p, readMemoryLimit)
+			}
+		}
+	}
+	return cpu, mem
+}
+
+// walkCgroup returns the lowest non-zero limit read by read from the cgroup
+// at path p under root and its ancestors.
+func walkCgroup[T float64 | uint64](root, p string, read func(dir string) T) T {
+	var limit T
+	for p = path.Clean("/" + p); ; p = path.Dir(p) {
+		if l := read(filepath.Join(root, filepath.FromSlash(p))); l > 0 && (limit == 0 || l < limit) {
+			limit = l
+		}
+		if p == "/" {
+			return limit
+		}
+	}
+}
+
+func minCPU(a, b float64) float64 {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func minMem(a, b uint64) uint64 {
+	if a == 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func readCgroupFile(dir, name string) string {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// readCPUMax reads the cgroup v2 CPU quota, such as "150000 100000".
+func readCPUMax(dir string) float64 {
+	f := strings.Fields(readCgroupFile(dir, "cpu.max"))
+	if len(f) != 2 {
+		return 0
+	}
+	return cpuQuota(f[0], f[1])
+}
+
+// readCFSQuota reads the cgroup v1 CPU quota.
+func readCFSQuota(dir string) float64 {
+	return cpuQuota(readCgroupFile(dir, "cpu.cfs_quota_us"), readCgroupFile(dir, "cpu.cfs_period_us"))
+}
+
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// readMemoryMax reads the cgroup v2 memory limit.
+func readMemoryMax(dir string) uint64 {
+	l, _ := strconv.ParseUint(readCgroupFile(dir, "memory.max"), 10, 64)
+	return l
+}
+
+// readMemoryLimit reads the cgroup v1 memory limit, which is close to the
+// maximum int64 when unlimited.
+func readMemoryLimit(dir string) uint64 {
+	l, _ := strconv.ParseUint(readCgroupFile(dir, "memory.limit_in_bytes"), 10, 64)
+	if l >= 1<<62 {
+		return 0
+	}
+	return l
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCgroupFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadCgroupLimitsV2(t *testing.T) {
+	root := t.TempDir()
+	writeCgroupFiles(t, root, map[string]string{
+		"app/cpu.max":        "max 100000",
+		"app/memory.max":     "1073741824",
+		"app/web/cpu.max":    "150000 100000",
+		"app/web/memory.max": "max",
+		"other/memory.max":   "1024",
+		"cpu.max":            "max 100000",
+	})
+	cpu, mem := readCgroupLimits(root, []byte("0::/app/web\n"))
+	if cpu != 1.5 {
+		t.Errorf("cpu = %v; want 1.5", cpu)
+	}
+	if mem != 1<<30 {
+		t.Errorf("mem = %v; want %v", mem, 1<<30)
+	}
+}
+
+func TestReadCgroupLimitsV1(t *testing.T) {
+	root := t.TempDir()
+	writeCgroupFiles(t, root, map[string]string{
+		"cpu,cpuacct/app/cpu.cfs_quota_us":  "50000",
+		"cpu,cpuacct/app/cpu.cfs_period_us": "100000",
+		"cpu,cpuacct/cpu.cfs_quota_us":      "-1",
+		"cpu,cpuacct/cpu.cfs_period_us":     "100000",
+		"memory/app/memory.limit_in_bytes":  "9223372036854771712",
+		"memory/memory.limit_in_bytes":      "536870912",
+		// Ignored, the v1 controllers take precedence.
+		"app/cpu.max":    "400000 100000",
+		"app/memory.max": "1024",
+	})
+	cpu, mem := readCgroupLimits(root, []byte("4:memory:/app\n2:cpu,cpuacct:/app\n0::/app\n"))
+	if cpu != 0.5 {
+		t.Errorf("cpu = %v; want 0.5", cpu)
+	}
+	if mem != 512<<20 {
+		t.Errorf("mem = %v; want %v", mem, 512<<20)
+	}
+}
+
+func TestReadCgroupLimitsNone(t *testing.T) {
+	cpu, mem := readCgroupLimits(t.TempDir(), []byte("0::/\n"))
+	if cpu != 0 || mem != 0 {
+		t.Errorf("limits = %v, %v; want none", cpu, mem)
+	}
+}
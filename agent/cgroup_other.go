@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package agent
+
+// cgroupLimits returns the CPU quota, in CPUs, and the memory limit, in
+// bytes, of the cgroups of the process. Zero means unlimited.
+func cgroupLimits() (cpu float64, mem uint64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/google/gops/internal"
+)
+
+// Handler runs a command registered with Register. It writes its output
+// to w. ctx is canceled when the client disconnects.
+type Handler func(ctx context.Context, args []string, w io.Writer) error
+
+type command struct {
+	description string
+	handler     Handler
+}
+
+var (
+	commandsMu sync.RWMutex
+	commands   = make(map[string]command)
+)
+
+// Register registers an application-specific command, such as a dump of
+// the configuration, to be listed with `gops commands` and run with
+// `gops call`.
+func Register(name, description string, handler Handler) {
+	if handler == nil {
+		panic("gops: nil handler for command " + name)
+	}
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	internal.CheckName("command", name, commands)
+	commands[name] = command{description: description, handler: handler}
+}
+
+func listCommands(_ io.Reader, w io.Writer) error {
+	commandsMu.RLock()
+	list := make([]internal.Command, 0, len(commands))
+	for name, c := range commands {
+		list = append(list, internal.Command{Name: name, Description: c.description})
+	}
+	commandsMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return json.NewEncoder(w).Encode(list)
+}
+
+func callCommand(r io.Reader, w io.Writer) (err error) {
+	var call internal.CommandCall
+	if err := json.NewDecoder(r).Decode(&call); err != nil {
+		return err
+	}
+	commandsMu.RLock()
+	c, ok := commands[call.Name]
+	commandsMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown command %q", call.Name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := closed(r)
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("command %s panicked: %v", call.Name, v)
+		}
+	}()
+	return c.handler(ctx, call.Args, w)
+}
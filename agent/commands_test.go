@@ -0,0 +1,83 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func TestCommands(t *testing.T) {
+	Register("test-echo", "Echoes its arguments.", func(_ context.Context, args []string, w io.Writer) error {
+		_, err := fmt.Fprintln(w, strings.Join(args, " "))
+		return err
+	})
+	Register("test-fail", "Fails.", func(context.Context, []string, io.Writer) error {
+		return errors.New("failed")
+	})
+	Register("test-panic", "Panics.", func(context.Context, []string, io.Writer) error {
+		panic("oops")
+	})
+
+	var buf bytes.Buffer
+	if err := listCommands(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var list []internal.Command
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) < 3 || list[0] != (internal.Command{Name: "test-echo", Description: "Echoes its arguments."}) {
+		t.Errorf("got commands %v", list)
+	}
+
+	call := func(name string, args ...string) (string, error) {
+		b, _ := json.Marshal(internal.CommandCall{Name: name, Args: args})
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		var buf bytes.Buffer
+		err := callCommand(io.MultiReader(bytes.NewReader(b), pr), &buf)
+		return buf.String(), err
+	}
+	if out, err := call("test-echo", "a", "b"); err != nil || out != "a b\n" {
+		t.Errorf("test-echo = %q, %v; want %q", out, err, "a b\n")
+	}
+	for _, name := range []string{"test-fail", "test-panic", "test-missing"} {
+		if _, err := call(name); err == nil {
+			t.Errorf("%s succeeded; want an error", name)
+		}
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	h := func(context.Context, []string, io.Writer) error { return nil }
+	Register("test-twice", "", h)
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a command twice didn't panic")
+		}
+	}()
+	Register("test-twice", "", h)
+}
+
+func TestCommandCanceled(t *testing.T) {
+	Register("test-wait", "", func(ctx context.Context, _ []string, _ io.Writer) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	b, _ := json.Marshal(internal.CommandCall{Name: "test-wait"})
+	// The client disconnects right away.
+	if err := callCommand(bytes.NewReader(b), io.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v; want %v", err, context.Canceled)
+	}
+}
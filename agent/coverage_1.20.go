@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.20
+// +build go1.20
+
+package agent
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/coverage"
+
+	"github.com/google/gops/internal"
+)
+
+func coverageData(r io.Reader, w io.Writer) error {
+	var opts internal.CoverageOptions
+	if err := json.NewDecoder(r).Decode(&opts); err != nil {
+		return err
+	}
+	// The files are written to a directory rather than streamed, for
+	// them to have the names expected by `go tool covdata`.
+	dir, err := os.MkdirTemp("", "gops-coverage")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := coverage.WriteMetaDir(dir); err != nil {
+		return err
+	}
+	if err := coverage.WriteCountersDir(dir); err != nil {
+		return err
+	}
+	if opts.Reset {
+		// Before anything is sent, for the error to be reported.
+		if err := coverage.ClearCounters(); err != nil {
+			return fmt.Errorf("can't reset the counters: %v", err)
+		}
+	}
+	return writeTar(w, dir)
+}
+
+// writeTar writes a tar archive of the files of dir to w.
+func writeTar(w io.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: e.Name(), Mode: 0o644, Size: int64(len(b))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(b); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
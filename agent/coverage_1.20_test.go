@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.20
+// +build go1.20
+
+package agent
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCoverageNotBuiltWithCover(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("built with -cover")
+	}
+	if err := coverageData(strings.NewReader("{}"), io.Discard); err == nil {
+		t.Error("got coverage data of a binary not built with -cover")
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "covmeta.abc"), []byte("meta"), 0o644)
+	os.WriteFile(filepath.Join(dir, "covcounters.abc.1.2"), []byte("counters"), 0o644)
+
+	var buf bytes.Buffer
+	if err := writeTar(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(b)
+	}
+	if len(files) != 2 || files["covmeta.abc"] != "meta" || files["covcounters.abc.1.2"] != "counters" {
+		t.Errorf("got files %q", files)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.20
+// +build !go1.20
+
+package agent
+
+import (
+	"errors"
+	"io"
+)
+
+func coverageData(io.Reader, io.Writer) error {
+	return errors.New("coverage requires Go 1.20 or later")
+}
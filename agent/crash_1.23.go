@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+// +build go1.23
+
+package agent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+// keepCrashReports is the number of crash reports of dead processes with
+// crash output kept when the agent starts.
+const keepCrashReports = 20
+
+// crashReport is the file the crash output goes to, if any.
+var crashReport string
+
+func startCrashOutput(gopsdir string) error {
+	dir := internal.CrashDir(gopsdir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	pruneCrashReports(dir)
+	name := filepath.Join(dir, internal.CrashFile(os.Getpid(), startTime))
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	// The runtime duplicates the file descriptor.
+	defer f.Close()
+	path, _ := os.Executable()
+	h := internal.CrashHeader{
+		PID:       os.Getpid(),
+		Path:      path,
+		GoVersion: runtime.Version(),
+		Start:     startTime,
+	}
+	if err := h.Write(f); err != nil {
+		os.Remove(name)
+		return err
+	}
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		os.Remove(name)
+		return err
+	}
+	crashReport = name
+	return nil
+}
+
+// stopCrashOutput stops writing the crash output to the crash report and
+// removes it, as the process didn't crash.
+func stopCrashOutput() {
+	if crashReport != "" {
+		debug.SetCrashOutput(nil, debug.CrashOptions{})
+		os.Remove(crashReport)
+		crashReport = ""
+	}
+}
+
+// pruneCrashReports removes the reports in dir of the processes that are
+// gone without crash output, as they exited without closing the agent, and
+// all but the keepCrashReports most recent ones of the other processes
+// that are gone.
+func pruneCrashReports(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type report struct {
+		file string
+		time time.Time
+	}
+	var crashed []report
+	for _, e := range entries {
+		// The name is <pid>-<start>, or <pid> for older agents.
+		s, _, _ := strings.Cut(e.Name(), "-")
+		pid, err := strconv.Atoi(s)
+		if err != nil || !e.Type().IsRegular() || processExists(pid) {
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		_, output, err := internal.ParseCrashReport(b)
+		if err != nil {
+			continue
+		}
+		if len(bytes.TrimSpace(output)) == 0 {
+			os.Remove(file)
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			crashed = append(crashed, report{file: file, time: info.ModTime()})
+		}
+	}
+	if len(crashed) <= keepCrashReports {
+		return
+	}
+	sort.Slice(crashed, func(i, j int) bool { return crashed[i].time.After(crashed[j].time) })
+	for _, r := range crashed[keepCrashReports:] {
+		os.Remove(r.file)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+// +build go1.23
+
+package agent
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPruneCrashReports(t *testing.T) {
+	// The PID of a process that is gone.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	dead := cmd.Process.Pid
+
+	dir := t.TempDir()
+	start := time.Date(2017, 1, 1, 15, 4, 5, 0, time.UTC)
+	write := func(pid int, start time.Time, output string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		h := internal.CrashHeader{PID: pid, Path: "/bin/app", GoVersion: "go1.23.0", Start: start}
+		if err := h.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteString(output)
+		file := filepath.Join(dir, internal.CrashFile(pid, start))
+		if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, start, start); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	exited := write(dead, start, "")
+	alive := write(os.Getpid(), start, "")
+	var crashed []string
+	for i := 0; i <= keepCrashReports; i++ {
+		crashed = append(crashed, write(dead, start.Add(time.Duration(i)*time.Minute), "panic: boom\n"))
+	}
+
+	pruneCrashReports(dir)
+	exists := func(file string) bool {
+		_, err := os.Stat(file)
+		return err == nil
+	}
+	if exists(exited) {
+		t.Error("the report of a process that exited without crash output was kept")
+	}
+	if !exists(alive) {
+		t.Error("the report of a running process was removed")
+	}
+	if exists(crashed[0]) {
+		t.Error("the oldest crash was kept")
+	}
+	for _, file := range crashed[1:] {
+		if !exists(file) {
+			t.Errorf("crash %s was removed", filepath.Base(file))
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.23
+// +build !go1.23
+
+package agent
+
+import "errors"
+
+func startCrashOutput(gopsdir string) error {
+	return errors.New("crash output requires Go 1.23 or later")
+}
+
+func stopCrashOutput() {}
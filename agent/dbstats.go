@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/gops/internal"
+)
+
+func dbStats(r io.Reader, w io.Writer) error {
+	var names []string
+	if err := json.NewDecoder(r).Decode(&names); err != nil {
+		return err
+	}
+	// Without agent/sqlstats, no database can be registered.
+	stats := []internal.DBStats{}
+	if internal.DBStatsOf != nil {
+		var err error
+		if stats, err = internal.DBStatsOf(names); err != nil {
+			return err
+		}
+	} else if len(names) > 0 {
+		return fmt.Errorf("no database %q", names[0])
+	}
+	return json.NewEncoder(w).Encode(stats)
+}
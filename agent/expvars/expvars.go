@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package expvars lets the gops agent report the variables published with
+// expvar, for the gops vars command. It is imported for its side effect:
+//
+//	import _ "github.com/google/gops/agent/expvars"
+//
+// Note that expvar publishes the cmdline and memstats variables and
+// registers /debug/vars on http.DefaultServeMux.
+package expvars
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+
+	"github.com/google/gops/internal"
+)
+
+func init() {
+	internal.Expvars = values
+}
+
+func values(names []string) (map[string]json.RawMessage, error) {
+	vs := make(map[string]json.RawMessage)
+	if len(names) == 0 {
+		expvar.Do(func(kv expvar.KeyValue) {
+			vs[kv.Key] = json.RawMessage(kv.Value.String())
+		})
+	}
+	for _, name := range names {
+		v := expvar.Get(name)
+		if v == nil {
+			return nil, fmt.Errorf("no variable %q", name)
+		}
+		vs[name] = json.RawMessage(v.String())
+	}
+	return vs, nil
+}
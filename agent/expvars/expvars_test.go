@@ -0,0 +1,34 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package expvars
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	expvar.NewInt("gops_test_requests").Set(42)
+
+	got, err := values([]string{"gops_test_requests"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || string(got["gops_test_requests"]) != "42" {
+		t.Errorf("got vars %s; want gops_test_requests only", got)
+	}
+
+	got, err = values(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["memstats"]; !ok {
+		t.Errorf("got all vars %s; want memstats among them", got)
+	}
+
+	if _, err := values([]string{"gops_test_missing"}); err == nil {
+		t.Error("got no error for a missing variable")
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"golang.org/x/sys/cpu"
+)
+
+// fd is an open file descriptor of the process.
+type fd struct {
+	num    int
+	kind   string
+	flags  string
+	target string
+}
+
+func fileDescriptors(_ io.Reader, w io.Writer) error {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return err
+	}
+	sockets := make(map[string]string)
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6", "unix"} {
+		b, err := os.ReadFile(filepath.Join("/proc/self/net", proto))
+		if err != nil {
+			continue
+		}
+		parseProcNet(proto, b, sockets)
+	}
+
+	var fds []fd
+	for _, e := range entries {
+		num, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		path := filepath.Join("/proc/self/fd", e.Name())
+		target, err := os.Readlink(path)
+		if err != nil {
+			// Closed since, such as the one used to read the directory.
+			continue
+		}
+		f := fd{num: num, kind: fdKind(path, target), target: target}
+		if info, err := os.ReadFile(filepath.Join("/proc/self/fdinfo", e.Name())); err == nil {
+			f.flags = fdFlags(info)
+		}
+		if f.kind == "socket" {
+			if s, ok := sockets[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")]; ok {
+				f.target = s
+			}
+		}
+		fds = append(fds, f)
+	}
+	sort.Slice(fds, func(i, j int) bool { return fds[i].num < fds[j].num })
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FD\tKIND\tFLAGS\tTARGET")
+	kinds := make(map[string]int)
+	for _, f := range fds {
+		kinds[f.kind]++
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", f.num, f.kind, f.flags, f.target)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	var summary []string
+	for kind, n := range kinds {
+		summary = append(summary, fmt.Sprintf("%s: %d", kind, n))
+	}
+	sort.Strings(summary)
+	_, err = fmt.Fprintf(w, "\ntotal: %d (%s)\n", len(fds), strings.Join(summary, ", "))
+	return err
+}
+
+// fdKind returns the kind of the file descriptor at path, linking to target.
+func fdKind(path, target string) string {
+	switch {
+	case strings.HasPrefix(target, "socket:"):
+		return "socket"
+	case strings.HasPrefix(target, "pipe:"):
+		return "pipe"
+	case strings.HasPrefix(target, "anon_inode:"):
+		// e.g. "anon_inode:[eventfd]" or "anon_inode:inotify"
+		return strings.Trim(strings.TrimPrefix(target, "anon_inode:"), "[]")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "file"
+	}
+	switch mode := info.Mode(); {
+	case mode.IsDir():
+		return "dir"
+	case mode&os.ModeCharDevice != 0:
+		return "char-device"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	}
+	return "file"
+}
+
+var openFlags = []struct {
+	flag int
+	name string
+}{
+	{syscall.O_APPEND, "O_APPEND"},
+	{syscall.O_NONBLOCK, "O_NONBLOCK"},
+	{syscall.O_CLOEXEC, "O_CLOEXEC"},
+	{syscall.O_SYNC, "O_SYNC"},
+	{syscall.O_DIRECT, "O_DIRECT"},
+}
+
+// fdFlags formats the open flags found in the content of a fdinfo file.
+func fdFlags(info []byte) string {
+	for _, line := range strings.Split(string(info), "\n") {
+		if !strings.HasPrefix(line, "flags:") {
+			continue
+		}
+		flags, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "flags:")), 8, 64)
+		if err != nil {
+			return ""
+		}
+		names := []string{"O_RDONLY"}
+		switch int(flags) & syscall.O_ACCMODE {
+		case syscall.O_WRONLY:
+			names[0] = "O_WRONLY"
+		case syscall.O_RDWR:
+			names[0] = "O_RDWR"
+		}
+		for _, f := range openFlags {
+			if int(flags)&f.flag == f.flag {
+				names = append(names, f.name)
+			}
+		}
+		return strings.Join(names, "|")
+	}
+	return ""
+}
+
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// parseProcNet adds the sockets described in the content of the
+// /proc/net file of proto to sockets, keyed by inode.
+func parseProcNet(proto string, b []byte, sockets map[string]string) {
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if proto == "unix" {
+			// Num RefCount Protocol Flags Type St Inode [Path]
+			if len(fields) < 7 {
+				continue
+			}
+			desc := "unix"
+			if len(fields) > 7 {
+				desc += " " + fields[7]
+			}
+			sockets[fields[6]] = desc
+			continue
+		}
+		// sl local_address rem_address st ... uid timeout inode
+		if len(fields) < 10 {
+			continue
+		}
+		desc := fmt.Sprintf("%s %s -> %s", proto, procNetAddr(fields[1]), procNetAddr(fields[2]))
+		if strings.HasPrefix(proto, "tcp") {
+			desc += " (" + tcpStates[fields[3]] + ")"
+		}
+		sockets[fields[9]] = desc
+	}
+}
+
+// procNetAddr formats an address such as "0100007F:1F90", in which the IP
+// address is made of 32-bit words in host byte order.
+func procNetAddr(s string) string {
+	ip, port, ok := strings.Cut(s, ":")
+	if !ok {
+		return s
+	}
+	b, err := hex.DecodeString(ip)
+	if err != nil || len(b)%4 != 0 {
+		return s
+	}
+	if !cpu.IsBigEndian {
+		for i := 0; i < len(b); i += 4 {
+			b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+		}
+	}
+	p, err := strconv.ParseUint(port, 16, 16)
+	if err != nil {
+		return s
+	}
+	return net.JoinHostPort(net.IP(b).String(), strconv.FormatUint(p, 10))
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseProcNet(t *testing.T) {
+	sockets := make(map[string]string)
+	parseProcNet("tcp", []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:B798 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1234 1 0000000000000000 100 0 0 10 0
+`), sockets)
+	parseProcNet("tcp6", []byte(`  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:1F90 00000000000000000000000001000000:D431 01 00000000:00000000 00:00000000 00000000     0        0 5678 1 0000000000000000 20 4 30 10 -1
+`), sockets)
+	parseProcNet("unix", []byte(`Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 91011 /run/app.sock
+`), sockets)
+	want := map[string]string{
+		"1234":  "tcp 127.0.0.1:47000 -> 0.0.0.0:0 (LISTEN)",
+		"5678":  "tcp6 [::1]:8080 -> [::1]:54321 (ESTABLISHED)",
+		"91011": "unix /run/app.sock",
+	}
+	for inode, desc := range want {
+		if sockets[inode] != desc {
+			t.Errorf("socket %v = %q; want %q", inode, sockets[inode], desc)
+		}
+	}
+}
+
+func TestFdFlags(t *testing.T) {
+	got := fdFlags([]byte("pos:\t0\nflags:\t02004002\nmnt_id:\t15\n"))
+	if want := "O_RDWR|O_NONBLOCK|O_CLOEXEC"; got != want {
+		t.Errorf("fdFlags() = %q; want %q", got, want)
+	}
+}
+
+func TestFileDescriptors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := fileDescriptors(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "FD ") || !strings.Contains(buf.String(), "\ntotal: ") {
+		t.Errorf("got:\n%s", buf.String())
+	}
+}
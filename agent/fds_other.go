@@ -0,0 +1,17 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package agent
+
+import (
+	"errors"
+	"io"
+)
+
+func fileDescriptors(_ io.Reader, _ io.Writer) error {
+	return errors.New("file descriptors are only listed on Linux")
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.25
+// +build go1.25
+
+package agent
+
+import (
+	"errors"
+	"io"
+	"runtime/trace"
+)
+
+var recorder *trace.FlightRecorder
+
+func startFlightRecorder(opts FlightRecorderOptions) error {
+	fr := trace.NewFlightRecorder(trace.FlightRecorderConfig{
+		MinAge:   opts.MinAge,
+		MaxBytes: opts.MaxBytes,
+	})
+	if err := fr.Start(); err != nil {
+		return err
+	}
+	recorder = fr
+	return nil
+}
+
+func stopFlightRecorder() {
+	if recorder != nil {
+		recorder.Stop()
+		recorder = nil
+	}
+}
+
+func traceSnapshot(_ io.Reader, w io.Writer) error {
+	mu.Lock()
+	fr := recorder
+	mu.Unlock()
+	if fr == nil {
+		return errors.New("flight recorder is not enabled")
+	}
+	_, err := fr.WriteTo(w)
+	return err
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.25
+// +build go1.25
+
+package agent
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTraceSnapshot(t *testing.T) {
+	if err := traceSnapshot(nil, &bytes.Buffer{}); err == nil {
+		t.Error("got snapshot without flight recorder; want error")
+	}
+	err := Listen(Options{FlightRecorder: &FlightRecorderOptions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+	var buf bytes.Buffer
+	if err := traceSnapshot(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("got empty snapshot")
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.25
+// +build !go1.25
+
+package agent
+
+import (
+	"errors"
+	"io"
+)
+
+var errNoFlightRecorder = errors.New("flight recorder requires Go 1.25 or later")
+
+func startFlightRecorder(opts FlightRecorderOptions) error {
+	return errNoFlightRecorder
+}
+
+func stopFlightRecorder() {}
+
+func traceSnapshot(_ io.Reader, _ io.Writer) error {
+	return errNoFlightRecorder
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// gcSentinel is an object whose finalizer runs after every garbage
+// collection. It contains a pointer so that it isn't tiny-allocated.
+type gcSentinel struct {
+	_ *byte
+}
+
+// notifyGC sends to the returned channel after garbage collections, until
+// done is closed. Notifications are dropped while the channel is full, so
+// a single notification may stand for several collections.
+func notifyGC(done <-chan struct{}) <-chan struct{} {
+	c := make(chan struct{}, 1)
+	var arm func()
+	arm = func() {
+		runtime.SetFinalizer(&gcSentinel{}, func(*gcSentinel) {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+			arm()
+		})
+	}
+	arm()
+	return c
+}
+
+// gcTracer formats the garbage collections that happened between two
+// reads of the memory stats.
+type gcTracer struct {
+	prev runtime.MemStats
+}
+
+// writeTo writes a line for every garbage collection that completed
+// between t.prev and s, then makes s the previous stats. The memory stats
+// only have the heap after the last collection, so the heap of the others
+// is printed as "n/a".
+func (t *gcTracer) writeTo(w io.Writer, s *runtime.MemStats) error {
+	defer func() { t.prev = *s }()
+	n := s.NumGC - t.prev.NumGC
+	if n == 0 {
+		return nil
+	}
+	if n > 256 {
+		// Older pauses are no longer recorded.
+		fmt.Fprintf(w, "... %d garbage collections skipped\n", n-256)
+		n = 256
+	}
+	// The heap before the last collection is estimated as the heap after
+	// the previous one, plus what was allocated since.
+	before := t.prev.HeapAlloc + (s.TotalAlloc - t.prev.TotalAlloc)
+	forced := s.NumForcedGC - t.prev.NumForcedGC
+	for gc := s.NumGC - n + 1; gc <= s.NumGC; gc++ {
+		i := (gc + 255) % 256
+		fmt.Fprintf(w, "gc %d @%s: ", gc, time.Unix(0, int64(s.PauseEnd[i])).Format("15:04:05.000"))
+		if gc == s.NumGC {
+			fmt.Fprintf(w, "%s->%s heap, %s goal, ", mb(before), mb(s.HeapAlloc), mb(s.NextGC))
+		} else {
+			fmt.Fprint(w, "n/a heap, n/a goal, ")
+		}
+		fmt.Fprintf(w, "%v pause, %.2f%% gc cpu", time.Duration(s.PauseNs[i]), s.GCCPUFraction*100)
+		switch {
+		case n == 1 && forced > 0:
+			fmt.Fprint(w, " (forced)")
+		case n > 1 && forced > 0 && gc == s.NumGC:
+			fmt.Fprintf(w, " (%d of the last %d forced)", forced, n)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mb(b uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(b)/(1<<20))
+}
+
+func gcTrace(r io.Reader, w io.Writer) error {
+	done := closed(r)
+	stop := make(chan struct{})
+	defer close(stop)
+	gcs := notifyGC(stop)
+
+	var t gcTracer
+	runtime.ReadMemStats(&t.prev)
+	// Let the client know that the stream started.
+	if _, err := w.Write(nil); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-gcs:
+		case <-done:
+			return nil
+		}
+		var s runtime.MemStats
+		runtime.ReadMemStats(&s)
+		if err := t.writeTo(w, &s); err != nil {
+			return err
+		}
+	}
+}
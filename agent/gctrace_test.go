@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGCTracer(t *testing.T) {
+	var tr gcTracer
+	runtime.ReadMemStats(&tr.prev)
+	runtime.GC()
+	runtime.GC()
+
+	var s runtime.MemStats
+	runtime.ReadMemStats(&s)
+	var buf bytes.Buffer
+	if err := tr.writeTo(&buf, &s); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines; want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "n/a heap, n/a goal") {
+		t.Errorf("got first line %q; want the heap n/a", lines[0])
+	}
+	if !strings.Contains(lines[1], "MB heap") || !strings.HasSuffix(lines[1], "(2 of the last 2 forced)") {
+		t.Errorf("got last line %q", lines[1])
+	}
+
+	buf.Reset()
+	if err := tr.writeTo(&buf, &s); err != nil || buf.Len() != 0 {
+		t.Errorf("got %q, %v without garbage collection; want nothing", buf.String(), err)
+	}
+}
+
+func TestNotifyGC(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	gcs := notifyGC(done)
+	runtime.GC()
+	select {
+	case <-gcs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification after garbage collection")
+	}
+}
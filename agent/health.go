@@ -0,0 +1,128 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+type health struct {
+	timeout time.Duration
+	check   func(ctx context.Context) error
+}
+
+var (
+	healthMu     sync.RWMutex
+	healthChecks = make(map[string]health)
+)
+
+// RegisterHealthCheck registers a named health check, such as a ping of
+// the database, to be run with `gops health`. The context passed to check
+// is canceled after timeout, if positive, or when the client disconnects.
+// A check that fails, panics or doesn't return before its timeout is
+// reported as failed.
+func RegisterHealthCheck(name string, timeout time.Duration, check func(ctx context.Context) error) {
+	if check == nil {
+		panic("gops: nil health check " + name)
+	}
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	internal.CheckName("health check", name, healthChecks)
+	healthChecks[name] = health{timeout: timeout, check: check}
+}
+
+func healthCheck(r io.Reader, w io.Writer) error {
+	var names []string
+	if err := json.NewDecoder(r).Decode(&names); err != nil {
+		return err
+	}
+	healthMu.RLock()
+	checks := make(map[string]health, len(healthChecks))
+	if len(names) == 0 {
+		for name, c := range healthChecks {
+			checks[name] = c
+		}
+	}
+	for _, name := range names {
+		c, ok := healthChecks[name]
+		if !ok {
+			healthMu.RUnlock()
+			return fmt.Errorf("no health check %q", name)
+		}
+		checks[name] = c
+	}
+	healthMu.RUnlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := closed(r)
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	results := make([]internal.HealthResult, 0, len(checks))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, c := range checks {
+		wg.Add(1)
+		go func(name string, c health) {
+			defer wg.Done()
+			res := c.run(ctx, name)
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		}(name, c)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return json.NewEncoder(w).Encode(results)
+}
+
+// run runs c, without waiting for it after its timeout.
+func (c health) run(ctx context.Context, name string) internal.HealthResult {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				errc <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		errc <- c.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", c.timeout)
+	}
+	res := internal.HealthResult{Name: name, Latency: time.Since(start)}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestHealthCheck(t *testing.T) {
+	RegisterHealthCheck("test-ok", time.Second, func(context.Context) error {
+		return nil
+	})
+	RegisterHealthCheck("test-fail", time.Second, func(context.Context) error {
+		return errors.New("database unreachable")
+	})
+	RegisterHealthCheck("test-panic", time.Second, func(context.Context) error {
+		panic("oops")
+	})
+	RegisterHealthCheck("test-stuck", 10*time.Millisecond, func(context.Context) error {
+		// Ignores the context.
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	check := func(names ...string) ([]internal.HealthResult, error) {
+		b, _ := json.Marshal(names)
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		var buf bytes.Buffer
+		if err := healthCheck(io.MultiReader(bytes.NewReader(b), pr), &buf); err != nil {
+			return nil, err
+		}
+		var results []internal.HealthResult
+		err := json.Unmarshal(buf.Bytes(), &results)
+		return results, err
+	}
+
+	results, err := check("test-stuck", "test-panic", "test-ok", "test-fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ name, err string }{
+		{"test-fail", "database unreachable"},
+		{"test-ok", ""},
+		{"test-panic", "panic: oops"},
+		{"test-stuck", "timed out after 10ms"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got results %v; want %d", results, len(want))
+	}
+	for i, w := range want {
+		if results[i].Name != w.name || results[i].Error != w.err {
+			t.Errorf("result %d = %q, %q; want %q, %q", i, results[i].Name, results[i].Error, w.name, w.err)
+		}
+	}
+	if results[3].Latency >= time.Second {
+		t.Errorf("waited %v for test-stuck", results[3].Latency)
+	}
+
+	if _, err := check("test-missing"); err == nil || !strings.Contains(err.Error(), "test-missing") {
+		t.Errorf("got error %v for a missing health check", err)
+	}
+}
+
+func TestRegisterHealthCheckTwice(t *testing.T) {
+	check := func(context.Context) error { return nil }
+	RegisterHealthCheck("test-twice", 0, check)
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a health check twice didn't panic")
+		}
+	}()
+	RegisterHealthCheck("test-twice", 0, check)
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"io"
+	"os"
+	"runtime/debug"
+)
+
+// heapDump writes a heap dump. The runtime only writes heap dumps to file
+// descriptors, so it goes through a temporary file.
+func heapDump(_ io.Reader, w io.Writer) error {
+	f, err := os.CreateTemp("", "gops-heapdump")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	debug.WriteHeapDump(f.Fd())
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
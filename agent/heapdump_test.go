@@ -0,0 +1,20 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHeapDump(t *testing.T) {
+	var buf bytes.Buffer
+	if err := heapDump(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("go1.7 heap dump\n")) {
+		t.Errorf("got heap dump starting with %q", buf.Bytes()[:16])
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+// historyTimeFormat sorts lexically in chronological order.
+const historyTimeFormat = "20060102T150405Z"
+
+// history is a directory keeping the files of the most recent captures.
+// The files of a capture are named after its time followed by their kind,
+// such as "20170101T150405Z-heap.pprof".
+type history struct {
+	dir  string
+	keep int // number of captures to keep
+}
+
+func newHistory(dir string, keep int) (*history, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &history{dir: dir, keep: keep}, nil
+}
+
+// removeDeadPIDDirs removes the subdirectories of dir named after the PID
+// of a process that is gone, such as the default directories of the
+// agents that ran before.
+func removeDeadPIDDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() || pid == os.Getpid() || processExists(pid) {
+			continue
+		}
+		os.RemoveAll(filepath.Join(dir, e.Name()))
+	}
+}
+
+// create creates the file of the given kind for the capture made at t.
+func (h *history) create(t time.Time, kind string) (*os.File, error) {
+	name := t.UTC().Format(historyTimeFormat) + "-" + kind
+	return os.Create(filepath.Join(h.dir, name))
+}
+
+// prune removes the files of all but the h.keep most recent captures.
+func (h *history) prune() error {
+	files, err := h.list()
+	if err != nil {
+		return err
+	}
+	captures := make(map[time.Time]bool)
+	for i := len(files) - 1; i >= 0; i-- {
+		f := files[i]
+		if !captures[f.Time] && len(captures) >= h.keep {
+			if err := os.Remove(filepath.Join(h.dir, f.Name)); err != nil {
+				return err
+			}
+			continue
+		}
+		captures[f.Time] = true
+	}
+	return nil
+}
+
+// list returns the files of the history, oldest first.
+func (h *history) list() ([]internal.HistoryFile, error) {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []internal.HistoryFile
+	for _, e := range entries {
+		ts, kind, ok := strings.Cut(e.Name(), "-")
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+		t, err := time.Parse(historyTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, internal.HistoryFile{
+			Name: e.Name(),
+			Time: t,
+			Kind: strings.TrimSuffix(kind, filepath.Ext(kind)),
+			Size: info.Size(),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// writeFile writes the content of the named file of the history to w.
+func (h *history) writeFile(w io.Writer, name string) error {
+	if name == "" || name != filepath.Base(name) {
+		return errors.New("invalid file name")
+	}
+	f, err := os.Open(filepath.Join(h.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errors.New("no such file: " + name)
+		}
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
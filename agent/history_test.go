@@ -0,0 +1,99 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	h, err := newHistory(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2017, 1, 1, 15, 4, 5, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		for _, kind := range []string{"cpu.pprof", "heap.pprof"} {
+			f, err := h.create(start.Add(time.Duration(i)*time.Minute), kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString(kind)
+			f.Close()
+		}
+	}
+	if err := h.prune(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := h.list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Name)
+	}
+	want := []string{
+		"20170101T150505Z-cpu.pprof", "20170101T150505Z-heap.pprof",
+		"20170101T150605Z-cpu.pprof", "20170101T150605Z-heap.pprof",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got files %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got files %q; want %q", got, want)
+		}
+	}
+	if files[0].Kind != "cpu" || !files[0].Time.Equal(start.Add(time.Minute)) || files[0].Size != 9 {
+		t.Errorf("got first file %+v", files[0])
+	}
+
+	var buf bytes.Buffer
+	if err := h.writeFile(&buf, want[1]); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "heap.pprof" {
+		t.Errorf("got content %q; want %q", buf.String(), "heap.pprof")
+	}
+	if err := h.writeFile(&buf, "../"+want[1]); err == nil {
+		t.Error("got no error for a file outside of the history")
+	}
+}
+
+func TestRemoveDeadPIDDirs(t *testing.T) {
+	// The PID of a process that is gone.
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	dead := strconv.Itoa(cmd.Process.Pid)
+
+	dir := t.TempDir()
+	for _, name := range []string{dead, strconv.Itoa(os.Getpid()), "other"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removeDeadPIDDirs(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if want := []string{strconv.Itoa(os.Getpid()), "other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got dirs %v; want %v", got, want)
+	}
+}
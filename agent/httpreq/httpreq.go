@@ -0,0 +1,26 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpreq keeps track of the HTTP requests being served, for the
+// gops requests command, without the agent itself depending on net/http.
+package httpreq
+
+import (
+	"net/http"
+
+	"github.com/google/gops/agent"
+)
+
+// Handler returns a handler that serves the requests with h and keeps
+// track of them while they are served, to be listed with `gops requests`
+// along with the goroutines serving them. Query strings aren't kept, as
+// they may hold secrets. Tracking a request costs a few microseconds, see
+// agent.TrackRequest.
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done := agent.TrackRequest(r.Method, r.URL.Path, r.RemoteAddr)
+		defer done()
+		h.ServeHTTP(w, r)
+	})
+}
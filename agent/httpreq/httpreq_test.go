@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpreq
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/agent"
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := agent.Listen(agent.Options{Addr: "127.0.0.1:0", ConfigDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	port, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(os.Getpid())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})))
+	defer srv.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := http.Get(srv.URL + "/export?token=secret")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	conn, err := net.Dial("tcp", "127.0.0.1:"+strings.TrimSpace(string(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte{signal.Requests}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) == 0 || out[0] != internal.StatusOK {
+		t.Fatalf("got reply %q", out)
+	}
+	var reqs []internal.Request
+	if err := json.Unmarshal(out[1:], &reqs); err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 || reqs[0].Method != "GET" || reqs[0].Path != "/export" || reqs[0].Goroutine == 0 {
+		t.Errorf("got requests %+v; want GET /export", reqs)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+type logLevelVar struct {
+	v *slog.LevelVar
+
+	// The level to revert to when revert fires, if not nil.
+	revert   *time.Timer
+	revertTo slog.Level
+	revertAt time.Time
+}
+
+var (
+	logLevelsMu sync.Mutex
+	logLevels   = make(map[string]*logLevelVar)
+)
+
+// RegisterLogLevel registers the level of a logger, to be read and changed
+// with `gops loglevel`, temporarily or not.
+func RegisterLogLevel(name string, level *slog.LevelVar) {
+	if level == nil {
+		panic("gops: nil level for logger " + name)
+	}
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	internal.CheckName("logger", name, logLevels)
+	logLevels[name] = &logLevelVar{v: level}
+}
+
+// set sets the level of l, and reverts it to the level preceding any
+// temporary change after d if positive. l.v is left alone if it was changed
+// by the application since.
+func (l *logLevelVar) set(level slog.Level, d time.Duration) {
+	revertTo := l.v.Level()
+	if l.revert != nil {
+		l.revert.Stop()
+		l.revert = nil
+		revertTo = l.revertTo
+	}
+	l.v.Set(level)
+	if d <= 0 {
+		return
+	}
+	l.revertTo = revertTo
+	l.revertAt = time.Now().Add(d)
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		logLevelsMu.Lock()
+		defer logLevelsMu.Unlock()
+		if l.revert != t {
+			return
+		}
+		l.revert = nil
+		if l.v.Level() == level {
+			l.v.Set(l.revertTo)
+		}
+	})
+	l.revert = t
+}
+
+func logLevel(r io.Reader, w io.Writer) error {
+	var change internal.LogLevelChange
+	if err := json.NewDecoder(r).Decode(&change); err != nil {
+		return err
+	}
+	var level slog.Level
+	if change.Level != "" {
+		if err := level.UnmarshalText([]byte(change.Level)); err != nil {
+			return err
+		}
+	}
+
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+	names := make([]string, 0, len(logLevels))
+	if change.Logger != "" {
+		if _, ok := logLevels[change.Logger]; !ok {
+			return fmt.Errorf("no logger %q", change.Logger)
+		}
+		names = append(names, change.Logger)
+	} else {
+		for name := range logLevels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	levels := make([]internal.LogLevel, 0, len(names))
+	for _, name := range names {
+		l := logLevels[name]
+		if change.Level != "" {
+			l.set(level, change.For)
+		}
+		ll := internal.LogLevel{Logger: name, Level: l.v.Level().String()}
+		if l.revert != nil {
+			revertAt := l.revertAt
+			ll.RevertTo = l.revertTo.String()
+			ll.RevertAt = &revertAt
+		}
+		levels = append(levels, ll)
+	}
+	return json.NewEncoder(w).Encode(levels)
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func changeLogLevel(t *testing.T, change internal.LogLevelChange) []internal.LogLevel {
+	t.Helper()
+	b, _ := json.Marshal(change)
+	var buf bytes.Buffer
+	if err := logLevel(bytes.NewReader(b), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var levels []internal.LogLevel
+	if err := json.Unmarshal(buf.Bytes(), &levels); err != nil {
+		t.Fatal(err)
+	}
+	return levels
+}
+
+func TestLogLevel(t *testing.T) {
+	var level slog.LevelVar
+	RegisterLogLevel("test-http", &level)
+
+	levels := changeLogLevel(t, internal.LogLevelChange{Logger: "test-http"})
+	if len(levels) != 1 || levels[0] != (internal.LogLevel{Logger: "test-http", Level: "INFO"}) {
+		t.Errorf("got levels %v", levels)
+	}
+	if b, _ := json.Marshal(levels[0]); bytes.Contains(b, []byte("Revert")) {
+		t.Errorf("got level %s; want no revert fields", b)
+	}
+
+	levels = changeLogLevel(t, internal.LogLevelChange{Logger: "test-http", Level: "warn"})
+	if level.Level() != slog.LevelWarn || len(levels) != 1 || levels[0].Level != "WARN" || levels[0].RevertTo != "" {
+		t.Errorf("got level %v and levels %v; want WARN", level.Level(), levels)
+	}
+
+	// Temporary changes revert to the level preceding the first one.
+	changeLogLevel(t, internal.LogLevelChange{Logger: "test-http", Level: "debug", For: time.Hour})
+	levels = changeLogLevel(t, internal.LogLevelChange{Logger: "test-http", Level: "debug-4", For: 50 * time.Millisecond})
+	if level.Level() != slog.LevelDebug-4 || len(levels) != 1 || levels[0].RevertTo != "WARN" || levels[0].RevertAt == nil {
+		t.Errorf("got level %v and levels %v; want DEBUG-4 reverting to WARN", level.Level(), levels)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for level.Level() != slog.LevelWarn && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if level.Level() != slog.LevelWarn {
+		t.Errorf("level = %v; want reverted to WARN", level.Level())
+	}
+
+	// The level set by the application since isn't reverted.
+	changeLogLevel(t, internal.LogLevelChange{Logger: "test-http", Level: "debug", For: 50 * time.Millisecond})
+	level.Set(slog.LevelError)
+	time.Sleep(100 * time.Millisecond)
+	if level.Level() != slog.LevelError {
+		t.Errorf("level = %v; want ERROR set by the application", level.Level())
+	}
+}
+
+func TestLogLevelErrors(t *testing.T) {
+	for _, change := range []internal.LogLevelChange{
+		{Logger: "test-missing"},
+		{Level: "verbose"},
+	} {
+		b, _ := json.Marshal(change)
+		if err := logLevel(bytes.NewReader(b), &bytes.Buffer{}); err == nil {
+			t.Errorf("change %+v succeeded; want an error", change)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.21
+// +build !go1.21
+
+package agent
+
+import (
+	"errors"
+	"io"
+)
+
+func logLevel(io.Reader, io.Writer) error {
+	return errors.New("log levels require Go 1.21 or later")
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/google/gops/internal"
+)
+
+const (
+	defaultLogLines = 1000
+
+	// maxLogLine bounds the size of a line kept by a log writer, longer
+	// lines being split.
+	maxLogLine = 64 << 10
+
+	// followBuffer is the number of lines buffered for a client following
+	// the logs, after which lines are dropped.
+	followBuffer = 1024
+)
+
+// logRing keeps the last lines written to the log writers and handlers.
+type logRing struct {
+	mu        sync.Mutex
+	used      bool
+	max       int
+	lines     []string // at most max lines, the oldest at start
+	start     int
+	followers map[*logFollower]struct{}
+}
+
+// logFollower receives the lines added to a logRing.
+type logFollower struct {
+	c       chan string
+	dropped int // guarded by logRing.mu
+}
+
+var logBuffer = &logRing{max: defaultLogLines, followers: make(map[*logFollower]struct{})}
+
+// SetLogLines sets the number of lines kept by the writers returned by
+// LogWriter and the handlers returned by LogHandler, 1000 by default.
+func SetLogLines(n int) {
+	if n <= 0 {
+		panic("gops: non-positive number of log lines")
+	}
+	logBuffer.mu.Lock()
+	defer logBuffer.mu.Unlock()
+	lines := logBuffer.last(n)
+	logBuffer.lines, logBuffer.start, logBuffer.max = lines, 0, n
+}
+
+func (r *logRing) add(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.lines) < r.max {
+		r.lines = append(r.lines, line)
+	} else {
+		r.lines[r.start] = line
+		r.start = (r.start + 1) % r.max
+	}
+	for f := range r.followers {
+		select {
+		case f.c <- line:
+		default:
+			f.dropped++
+		}
+	}
+}
+
+// last returns the last n lines, or all of them if n isn't positive.
+func (r *logRing) last(n int) []string {
+	if n <= 0 || n > len(r.lines) {
+		n = len(r.lines)
+	}
+	lines := make([]string, 0, n)
+	for i := len(r.lines) - n; i < len(r.lines); i++ {
+		lines = append(lines, r.lines[(r.start+i)%len(r.lines)])
+	}
+	return lines
+}
+
+// tail returns the last n lines and, if follow is set, a follower of the
+// next ones, to be removed with unfollow.
+func (r *logRing) tail(n int, follow bool) ([]string, *logFollower) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var f *logFollower
+	if follow {
+		f = &logFollower{c: make(chan string, followBuffer)}
+		r.followers[f] = struct{}{}
+	}
+	return r.last(n), f
+}
+
+func (r *logRing) unfollow(f *logFollower) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.followers, f)
+}
+
+// takeDropped returns and resets the number of lines dropped for f.
+func (r *logRing) takeDropped(f *logFollower) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := f.dropped
+	f.dropped = 0
+	return n
+}
+
+// LogWriter returns a writer that writes to w and keeps the last lines
+// written, to be printed with `gops logs` when the output of the program
+// isn't reachable, e.g. with log.SetOutput(agent.LogWriter(os.Stderr)).
+// w may be nil to only keep the lines. The lines written to all the
+// writers and handlers share the same buffer, sized with SetLogLines.
+func LogWriter(w io.Writer) io.Writer {
+	logBuffer.mu.Lock()
+	logBuffer.used = true
+	logBuffer.mu.Unlock()
+	return &logWriter{w: w}
+}
+
+type logWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	partial []byte // last line, not terminated yet
+}
+
+func (lw *logWriter) Write(p []byte) (n int, err error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	n = len(p)
+	if lw.w != nil {
+		n, err = lw.w.Write(p)
+	}
+	b := append(lw.partial, p[:n]...)
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+		logBuffer.add(string(b[:i]))
+		b = b[i+1:]
+	}
+	for len(b) >= maxLogLine {
+		logBuffer.add(string(b[:maxLogLine]))
+		b = b[maxLogLine:]
+	}
+	lw.partial = append(lw.partial[:0], b...)
+	return n, err
+}
+
+func logs(r io.Reader, w io.Writer) error {
+	var opts internal.LogsOptions
+	if err := json.NewDecoder(r).Decode(&opts); err != nil {
+		return err
+	}
+	logBuffer.mu.Lock()
+	used := logBuffer.used
+	logBuffer.mu.Unlock()
+	if !used {
+		return errors.New("no logs kept, the program must log through agent.LogWriter or agent.LogHandler")
+	}
+
+	lines, f := logBuffer.tail(opts.Lines, opts.Follow)
+	if f != nil {
+		defer logBuffer.unfollow(f)
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if f == nil {
+		return nil
+	}
+	// Let the client know that the stream started.
+	if _, err := w.Write(nil); err != nil {
+		return err
+	}
+	done := closed(r)
+	for {
+		select {
+		case line := <-f.c:
+			if n := logBuffer.takeDropped(f); n > 0 {
+				fmt.Fprintf(w, "... %d lines dropped\n", n)
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		case <-done:
+			return nil
+		}
+	}
+}
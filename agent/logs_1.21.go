@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler returns a handler that passes the records to h and keeps the
+// last ones, formatted as by slog.TextHandler, to be printed with
+// `gops logs`. The records share the buffer of LogWriter.
+func LogHandler(h slog.Handler) slog.Handler {
+	return &logHandler{h: h, text: slog.NewTextHandler(LogWriter(nil), nil)}
+}
+
+type logHandler struct {
+	h    slog.Handler
+	text slog.Handler
+}
+
+func (l *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.h.Enabled(ctx, level)
+}
+
+func (l *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	// The text handler only fails if writing fails, which it doesn't.
+	l.text.Handle(ctx, r)
+	return l.h.Handle(ctx, r)
+}
+
+func (l *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{h: l.h.WithAttrs(attrs), text: l.text.WithAttrs(attrs)}
+}
+
+func (l *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{h: l.h.WithGroup(name), text: l.text.WithGroup(name)}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package agent
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(LogHandler(slog.NewJSONHandler(&out, nil))).With("request", 42).WithGroup("db")
+	logger.Info("query failed", "table", "users")
+	logger.Debug("not enabled")
+
+	if !strings.Contains(out.String(), `"msg":"query failed"`) {
+		t.Errorf("wrapped handler got %q", out.String())
+	}
+	lines, _ := logBuffer.tail(1, false)
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], `level=INFO msg="query failed" request=42 db.table=users`) {
+		t.Errorf("kept %q", lines)
+	}
+}
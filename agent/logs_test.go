@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func TestLogWriter(t *testing.T) {
+	defer SetLogLines(defaultLogLines)
+	SetLogLines(3)
+
+	var out bytes.Buffer
+	w := LogWriter(&out)
+	fmt.Fprint(w, "one\ntwo\nth")
+	fmt.Fprint(w, "ree\nfour\nfive\nsix")
+	if want := "one\ntwo\nthree\nfour\nfive\nsix"; out.String() != want {
+		t.Errorf("wrote %q; want %q", out.String(), want)
+	}
+	lines, _ := logBuffer.tail(0, false)
+	if want := []string{"three", "four", "five"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("kept %q; want %q", lines, want)
+	}
+	lines, _ = logBuffer.tail(2, false)
+	if want := []string{"four", "five"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("last 2 lines = %q; want %q", lines, want)
+	}
+
+	SetLogLines(2)
+	lines, _ = logBuffer.tail(0, false)
+	if want := []string{"four", "five"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("kept %q after resizing; want %q", lines, want)
+	}
+}
+
+func TestLogsFollow(t *testing.T) {
+	w := LogWriter(nil)
+	fmt.Fprintln(w, "before")
+
+	b, _ := json.Marshal(internal.LogsOptions{Lines: 1, Follow: true})
+	pr, pw := io.Pipe()
+	out, outw := io.Pipe()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- logs(io.MultiReader(bytes.NewReader(b), pr), outw)
+		outw.Close()
+	}()
+
+	s := bufio.NewScanner(out)
+	if !s.Scan() || s.Text() != "before" {
+		t.Fatalf("got first line %q; want %q", s.Text(), "before")
+	}
+	// The follower is registered before the first lines are sent.
+	fmt.Fprintln(w, "after")
+	if !s.Scan() || s.Text() != "after" {
+		t.Fatalf("got followed line %q; want %q", s.Text(), "after")
+	}
+
+	// The client disconnects.
+	pw.Close()
+	go io.Copy(io.Discard, out)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	logBuffer.mu.Lock()
+	n := len(logBuffer.followers)
+	logBuffer.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d followers left", n)
+	}
+}
+
+func TestLogsUnused(t *testing.T) {
+	logBuffer.mu.Lock()
+	used := logBuffer.used
+	logBuffer.used = false
+	logBuffer.mu.Unlock()
+	defer func() {
+		logBuffer.mu.Lock()
+		logBuffer.used = used
+		logBuffer.mu.Unlock()
+	}()
+	err := logs(strings.NewReader("{}"), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "LogWriter") {
+		t.Errorf("got error %v; want an error explaining how to keep logs", err)
+	}
+}
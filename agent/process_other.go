@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build js || plan9 || wasip1
+// +build js plan9 wasip1
+
+package agent
+
+// processExists reports whether a process with the given PID is running,
+// which is assumed when it can't be checked.
+func processExists(int) bool {
+	return true
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import "os"
+
+// processExists reports whether a process with the given PID is running.
+func processExists(pid int) bool {
+	// FindProcess opens the process on Windows, which fails if it is gone.
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
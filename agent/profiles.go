@@ -0,0 +1,164 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"time"
+)
+
+// ProfileHistoryOptions configures the periodic capture of profiles.
+type ProfileHistoryOptions struct {
+	// Interval is the time between two captures. Defaults to 10 minutes.
+	Interval time.Duration
+
+	// CPUDuration is how long the CPU is profiled at every capture.
+	// Defaults to 10 seconds.
+	CPUDuration time.Duration
+
+	// Keep is the number of captures kept, older ones being removed.
+	// Defaults to 24.
+	Keep int
+
+	// Dir is the directory the profiles are stored in. Defaults to
+	// profiles/<pid> in the config directory, where the directories of
+	// the processes that are gone are removed. Setting it to a fixed
+	// directory keeps the history across restarts.
+	Dir string
+}
+
+var (
+	profiles     *history
+	stopProfiles func()
+)
+
+func startProfileHistory(opts ProfileHistoryOptions, gopsdir string) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Minute
+	}
+	if opts.CPUDuration <= 0 {
+		opts.CPUDuration = 10 * time.Second
+	}
+	if opts.CPUDuration > opts.Interval {
+		opts.CPUDuration = opts.Interval
+	}
+	if opts.Keep <= 0 {
+		opts.Keep = 24
+	}
+	if opts.Dir == "" {
+		dir := filepath.Join(gopsdir, "profiles")
+		removeDeadPIDDirs(dir)
+		opts.Dir = filepath.Join(dir, strconv.Itoa(os.Getpid()))
+	}
+	h, err := newHistory(opts.Dir, opts.Keep)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(opts.Interval)
+		defer t.Stop()
+		for {
+			if err := captureProfiles(h, opts.CPUDuration, done); err != nil {
+				fmt.Fprintf(os.Stderr, "gops: profile history: %v\n", err)
+			}
+			select {
+			case <-t.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+	profiles = h
+	stopProfiles = func() {
+		close(done)
+		<-stopped
+	}
+	return nil
+}
+
+func stopProfileHistory() {
+	if stopProfiles != nil {
+		stopProfiles()
+		stopProfiles = nil
+		profiles = nil
+	}
+}
+
+// captureProfiles writes a CPU profile of the given duration, unless done
+// is closed before, followed by a heap profile.
+func captureProfiles(h *history, cpu time.Duration, done <-chan struct{}) error {
+	now := time.Now()
+	f, err := h.create(now, "cpu.pprof")
+	if err != nil {
+		return err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		// Most likely profiled by `gops pprof-cpu`, keep the heap profile.
+		f.Close()
+		os.Remove(f.Name())
+	} else {
+		select {
+		case <-time.After(cpu):
+		case <-done:
+		}
+		pprof.StopCPUProfile()
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	f, err = h.create(now, "heap.pprof")
+	if err != nil {
+		return err
+	}
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return h.prune()
+}
+
+var errNoProfileHistory = errors.New("profile history is not enabled")
+
+func listProfiles(_ io.Reader, w io.Writer) error {
+	mu.Lock()
+	h := profiles
+	mu.Unlock()
+	if h == nil {
+		return errNoProfileHistory
+	}
+	files, err := h.list()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(files)
+}
+
+func fetchProfile(r io.Reader, w io.Writer) error {
+	var name string
+	if err := json.NewDecoder(r).Decode(&name); err != nil {
+		return err
+	}
+	mu.Lock()
+	h := profiles
+	mu.Unlock()
+	if h == nil {
+		return errNoProfileHistory
+	}
+	return h.writeFile(w, name)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+var (
+	requestsMu     sync.Mutex
+	lastRequestID  uint64
+	activeRequests = make(map[uint64]internal.Request)
+)
+
+// TrackRequest records a request being served by the calling goroutine,
+// to be listed with `gops requests` along with the stack of the goroutine,
+// until the returned function is called. Package agent/httpreq tracks the
+// requests served by a net/http handler.
+//
+// The ID of the goroutine is read from its stack trace, which takes a few
+// microseconds per request, so TrackRequest is meant for requests taking
+// much longer than that.
+func TrackRequest(method, path, remote string) (done func()) {
+	id := startRequest(internal.Request{
+		Method:    method,
+		Path:      path,
+		Remote:    remote,
+		Start:     time.Now(),
+		Goroutine: goroutineID(),
+	})
+	return func() { endRequest(id) }
+}
+
+func startRequest(req internal.Request) uint64 {
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	lastRequestID++
+	activeRequests[lastRequestID] = req
+	return lastRequestID
+}
+
+func endRequest(id uint64) {
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	delete(activeRequests, id)
+}
+
+// goroutineID returns the ID of the calling goroutine, as printed in the
+// header of its stack trace, e.g. "goroutine 7 [running]:".
+func goroutineID() int64 {
+	var buf [32]byte
+	fields := strings.Fields(string(buf[:runtime.Stack(buf[:], false)]))
+	if len(fields) < 2 || fields[0] != "goroutine" {
+		return 0
+	}
+	id, _ := strconv.ParseInt(fields[1], 10, 64)
+	return id
+}
+
+func requests(_ io.Reader, w io.Writer) error {
+	now := time.Now()
+	requestsMu.Lock()
+	list := make([]internal.Request, 0, len(activeRequests))
+	for _, req := range activeRequests {
+		req.Age = now.Sub(req.Start)
+		list = append(list, req)
+	}
+	requestsMu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Age > list[j].Age })
+	return json.NewEncoder(w).Encode(list)
+}
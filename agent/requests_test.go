@@ -0,0 +1,42 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func TestTrackRequest(t *testing.T) {
+	listRequests := func() []internal.Request {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := requests(nil, &buf); err != nil {
+			t.Fatal(err)
+		}
+		var reqs []internal.Request
+		if err := json.Unmarshal(buf.Bytes(), &reqs); err != nil {
+			t.Fatal(err)
+		}
+		return reqs
+	}
+
+	done := TrackRequest("GET", "/export", "10.0.0.1:52290")
+	reqs := listRequests()
+	if len(reqs) != 1 {
+		t.Fatalf("got requests %v; want 1", reqs)
+	}
+	if req, id := reqs[0], goroutineID(); req.Method != "GET" || req.Path != "/export" || req.Goroutine != id || id == 0 || req.Age <= 0 {
+		t.Errorf("got request %+v; want GET /export served by goroutine %d", req, id)
+	}
+
+	done()
+	if reqs := listRequests(); len(reqs) != 0 {
+		t.Errorf("got requests %v after they completed", reqs)
+	}
+}
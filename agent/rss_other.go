@@ -0,0 +1,13 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package agent
+
+// readRSS returns the resident set size of the process in bytes.
+func readRSS() (uint64, bool) {
+	return 0, false
+}
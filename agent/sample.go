@@ -0,0 +1,215 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/gops/goroutine"
+	"github.com/google/gops/internal"
+	"github.com/google/gops/internal/profile"
+)
+
+// sampledStack is a stack seen in goroutine samples.
+type sampledStack struct {
+	state     string
+	frames    []goroutine.Frame
+	createdBy *goroutine.Frame
+	count     int // number of goroutine samples
+}
+
+// stackSampler aggregates the stacks of goroutines over samples.
+type stackSampler struct {
+	filter  *stackFilter
+	probe   bool
+	stacks  map[string]*sampledStack
+	samples int
+}
+
+func newStackSampler(opts internal.StackOptions) (*stackSampler, error) {
+	if opts.GroupBy != "" {
+		return nil, errors.New("grouping by label is not supported when sampling")
+	}
+	f, err := newStackFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &stackSampler{filter: f, probe: opts.NeedLabels(), stacks: make(map[string]*sampledStack)}, nil
+}
+
+// sample adds the goroutines of the debug=2 dump read from r, whose first
+// goroutine is the one writing it.
+func (s *stackSampler) sample(r io.Reader) error {
+	gs, err := goroutine.Parse(r)
+	if err != nil {
+		return err
+	}
+	if len(gs) == 0 {
+		return nil
+	}
+	if s.probe && gs[0].Labels[probeKey] != probeValue {
+		return errNoTracebackLabels
+	}
+	s.samples++
+	for _, g := range gs[1:] {
+		if !servesClient(g) && s.filter.match(g) {
+			s.add(g)
+		}
+	}
+	return nil
+}
+
+// The functions of the goroutines serving gops clients.
+var (
+	handleFunc = funcName(handle)
+	closedFunc = funcName(closed)
+)
+
+func funcName(f interface{}) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
+
+// servesClient reports whether g serves a gops client, such as the one
+// sampling the stacks.
+func servesClient(g *goroutine.Goroutine) bool {
+	if g.CreatedBy != nil && g.CreatedBy.Func == closedFunc {
+		return true
+	}
+	for _, f := range g.Frames {
+		if f.Func == handleFunc {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stackSampler) add(g *goroutine.Goroutine) {
+	var key strings.Builder
+	key.WriteString(g.State)
+	frames := g.Frames
+	if g.CreatedBy != nil {
+		frames = append(frames[:len(frames):len(frames)], *g.CreatedBy)
+	}
+	for _, f := range frames {
+		fmt.Fprintf(&key, "\n%s %s:%d", f.Func, f.File, f.Line)
+	}
+	st, ok := s.stacks[key.String()]
+	if !ok {
+		st = &sampledStack{state: g.State, frames: g.Frames, createdBy: g.CreatedBy}
+		s.stacks[key.String()] = st
+	}
+	st.count++
+}
+
+// sorted returns the stacks, most frequent first.
+func (s *stackSampler) sorted() []*sampledStack {
+	stacks := make([]*sampledStack, 0, len(s.stacks))
+	for _, st := range s.stacks {
+		stacks = append(stacks, st)
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		if stacks[i].count != stacks[j].count {
+			return stacks[i].count > stacks[j].count
+		}
+		return stacks[i].state < stacks[j].state
+	})
+	return stacks
+}
+
+// writeText writes at most limit of the most frequent stacks to w, or all
+// of them if limit is 0.
+func (s *stackSampler) writeText(w io.Writer, d, interval time.Duration, limit int) {
+	stacks := s.sorted()
+	var total int
+	for _, st := range stacks {
+		total += st.count
+	}
+	fmt.Fprintf(w, "%d samples over %v, every %v\n", s.samples, d, interval)
+	if limit > 0 && len(stacks) > limit {
+		stacks = stacks[:limit]
+	}
+	for _, st := range stacks {
+		fmt.Fprintf(w, "\n%.2f goroutines on average (%.1f%%) [%s]:\n",
+			float64(st.count)/float64(s.samples), 100*float64(st.count)/float64(total), st.state)
+		for _, f := range st.frames {
+			fmt.Fprintf(w, "%s\n\t%s:%d\n", f.Func, f.File, f.Line)
+		}
+		if f := st.createdBy; f != nil {
+			fmt.Fprintf(w, "created by %s\n\t%s:%d\n", f.Func, f.File, f.Line)
+		}
+	}
+}
+
+// profile returns the stacks as a pprof profile of the number of samples
+// and of the time spent, with the state of the goroutines as a label.
+func (s *stackSampler) profile(start time.Time, d, interval time.Duration) ([]byte, error) {
+	b := profile.NewBuilder(start, d,
+		[]profile.ValueType{{Type: "samples", Unit: "count"}, {Type: "time", Unit: "nanoseconds"}},
+		profile.ValueType{Type: "wall", Unit: "nanoseconds"}, int64(interval))
+	for _, st := range s.sorted() {
+		frames := make([]internal.Frame, len(st.frames))
+		for i, f := range st.frames {
+			frames[i] = internal.Frame{Func: f.Func, File: f.File, Line: f.Line}
+		}
+		b.Add(frames, []int64{int64(st.count), int64(st.count) * int64(interval)},
+			map[string]string{"state": st.state})
+	}
+	return b.Bytes()
+}
+
+func sampleStacks(r io.Reader, w io.Writer) error {
+	var opts internal.SampleOptions
+	if err := json.NewDecoder(r).Decode(&opts); err != nil {
+		return err
+	}
+	if opts.Duration <= 0 {
+		return errors.New("sampling duration must be positive")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 100 * time.Millisecond
+	}
+	s, err := newStackSampler(opts.StackOptions)
+	if err != nil {
+		return err
+	}
+	done := closed(r)
+	t := time.NewTicker(opts.Interval)
+	defer t.Stop()
+	start := time.Now()
+	end := time.After(opts.Duration)
+loop:
+	for {
+		dump := goroutineDump(2, s.probe)
+		err := s.sample(dump)
+		dump.Close()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-t.C:
+		case <-end:
+			break loop
+		case <-done:
+			return nil
+		}
+	}
+
+	d := time.Since(start).Round(time.Millisecond)
+	var text strings.Builder
+	s.writeText(&text, d, opts.Interval, opts.Limit)
+	p, err := s.profile(start, d, opts.Interval)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(internal.StackSample{Text: text.String(), Profile: p})
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestStackSampler(t *testing.T) {
+	s, err := newStackSampler(internal.StackOptions{State: "chan receive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		dump := testDump
+		if i%2 == 1 {
+			// Goroutine 9 is gone.
+			dump = dump[:strings.Index(dump, "goroutine 9 ")]
+		}
+		if err := s.sample(strings.NewReader(dump)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	s.writeText(&buf, time.Second, 250*time.Millisecond, 0)
+	want := `4 samples over 1s, every 250ms
+
+1.00 goroutines on average (66.7%) [chan receive]:
+main.worker
+	/src/worker.go:20
+created by main.main
+	/src/main.go:8
+
+0.50 goroutines on average (33.3%) [chan receive]:
+main.(*pool).wait
+	/src/pool.go:30
+created by main.main
+	/src/main.go:9
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	s.writeText(&buf, time.Second, 250*time.Millisecond, 1)
+	if strings.Contains(buf.String(), "pool") {
+		t.Errorf("got more stacks than the limit:\n%s", buf.String())
+	}
+
+	p, err := s.profile(time.Now(), time.Second, 250*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(p, []byte{0x1f, 0x8b}) {
+		t.Errorf("got profile starting with %x; want it gzipped", p[:2])
+	}
+}
+
+func TestSampleStacks(t *testing.T) {
+	var buf bytes.Buffer
+	opts := `{"Duration": 50000000, "Interval": 10000000, "Func": "^testing\\."}`
+	// The client keeps the connection open while sampling.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	if err := sampleStacks(io.MultiReader(strings.NewReader(opts), pr), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var s internal.StackSample
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s.Text, "testing.(*T).Run") {
+		t.Errorf("got:\n%s\nwant the test runner", s.Text)
+	}
+	if len(s.Profile) == 0 {
+		t.Error("got no profile")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sqlstats lets the gops agent report the connection pool
+// statistics of database/sql databases, for the gops dbstats command.
+package sqlstats
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/gops/internal"
+)
+
+var (
+	dbsMu sync.RWMutex
+	dbs   = make(map[string]*sql.DB)
+)
+
+func init() {
+	internal.DBStatsOf = stats
+}
+
+// Register registers a database, to print the statistics of its
+// connection pool with `gops dbstats`, under a name following the rules
+// of package agent.
+func Register(name string, db *sql.DB) {
+	if db == nil {
+		panic("gops: nil database " + name)
+	}
+	dbsMu.Lock()
+	defer dbsMu.Unlock()
+	internal.CheckName("database", name, dbs)
+	dbs[name] = db
+}
+
+func stats(names []string) ([]internal.DBStats, error) {
+	dbsMu.RLock()
+	defer dbsMu.RUnlock()
+	if len(names) == 0 {
+		for name := range dbs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	stats := make([]internal.DBStats, 0, len(names))
+	for _, name := range names {
+		db, ok := dbs[name]
+		if !ok {
+			return nil, fmt.Errorf("no database %q", name)
+		}
+		s := db.Stats()
+		stats = append(stats, internal.DBStats{Name: name, Stats: internal.DBPoolStats{
+			MaxOpenConnections: s.MaxOpenConnections,
+			OpenConnections:    s.OpenConnections,
+			InUse:              s.InUse,
+			Idle:               s.Idle,
+			WaitCount:          s.WaitCount,
+			WaitDuration:       s.WaitDuration,
+			MaxIdleClosed:      s.MaxIdleClosed,
+			MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  s.MaxLifetimeClosed,
+		}})
+	}
+	return stats, nil
+}
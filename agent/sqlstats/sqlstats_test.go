@@ -0,0 +1,42 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sqlstats
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// nopDriver is a driver that can't connect, whose pool stats are still
+// available.
+type nopDriver struct{}
+
+func (nopDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("can't connect")
+}
+
+func TestStats(t *testing.T) {
+	sql.Register("gops-nop", nopDriver{})
+	db, err := sql.Open("gops-nop", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(5)
+	Register("test-main", db)
+
+	got, err := stats([]string{"test-main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "test-main" || got[0].Stats.MaxOpenConnections != 5 {
+		t.Errorf("got stats %+v", got)
+	}
+	if _, err := stats([]string{"test-missing"}); err == nil {
+		t.Error("got stats of a missing database")
+	}
+}
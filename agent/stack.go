@@ -0,0 +1,338 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/gops/goroutine"
+	"github.com/google/gops/internal"
+)
+
+// The probe label is set on the goroutine writing the dump when filtering
+// by label, to find out whether the runtime prints labels in debug=2 dumps.
+// That goroutine is the first one of the dump.
+const (
+	probeKey   = "gops"
+	probeValue = "stack"
+)
+
+// errNoTracebackLabels is returned when debug=2 dumps don't have labels,
+// before Go 1.27 or with GODEBUG=tracebacklabels=0.
+var errNoTracebackLabels = errors.New("goroutine labels are not printed in tracebacks")
+
+// stackFilter selects goroutines from a debug=2 goroutine dump.
+type stackFilter struct {
+	opts   internal.StackOptions
+	fn     *regexp.Regexp
+	ids    map[int64]bool
+	labels []labelMatch
+	probe  bool // whether the first goroutine is the probe
+
+	// groups counts the goroutines by state for each value of the
+	// opts.GroupBy label, if set.
+	groups map[string]map[string]int
+}
+
+// labelMatch matches a label with the given key, and value unless any is
+// set.
+type labelMatch struct {
+	key, value string
+	any        bool
+}
+
+func newStackFilter(opts internal.StackOptions) (*stackFilter, error) {
+	f := &stackFilter{opts: opts}
+	if opts.Func != "" {
+		re, err := regexp.Compile(opts.Func)
+		if err != nil {
+			return nil, fmt.Errorf("invalid function regexp: %v", err)
+		}
+		f.fn = re
+	}
+	if len(opts.IDs) > 0 {
+		f.ids = make(map[int64]bool, len(opts.IDs))
+		for _, id := range opts.IDs {
+			f.ids[id] = true
+		}
+	}
+	for _, l := range opts.Labels {
+		k, v, ok := strings.Cut(l, "=")
+		f.labels = append(f.labels, labelMatch{key: k, value: v, any: !ok})
+	}
+	if opts.GroupBy != "" {
+		f.groups = make(map[string]map[string]int)
+	}
+	return f, nil
+}
+
+// match reports whether g matches f.
+func (f *stackFilter) match(g *goroutine.Goroutine) bool {
+	if f.ids != nil && !f.ids[g.ID] {
+		return false
+	}
+	if f.opts.State != "" && g.State != f.opts.State {
+		return false
+	}
+	if g.Wait < f.opts.MinWait {
+		return false
+	}
+	if !f.matchLabels(g.Labels) {
+		return false
+	}
+	if f.fn != nil {
+		for _, frame := range g.Frames {
+			if f.fn.MatchString(frame.Func) {
+				return true
+			}
+		}
+		return g.CreatedBy != nil && f.fn.MatchString(g.CreatedBy.Func)
+	}
+	return true
+}
+
+func (f *stackFilter) matchLabels(labels map[string]string) bool {
+	for _, l := range f.labels {
+		v, ok := labels[l.key]
+		if !ok || (!l.any && v != l.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// group returns the name of the group of goroutines with labels.
+func (f *stackFilter) group(labels map[string]string) string {
+	if v, ok := labels[f.opts.GroupBy]; ok {
+		return f.opts.GroupBy + "=" + v
+	}
+	return f.opts.GroupBy + " unset"
+}
+
+// writeTo writes the goroutines of the debug=2 dump read from r that match
+// f to w.
+func (f *stackFilter) writeTo(w io.Writer, r io.Reader) error {
+	var (
+		n     int
+		block bytes.Buffer
+	)
+	flush := func() error {
+		defer block.Reset()
+		if block.Len() == 0 {
+			return nil
+		}
+		gs, err := goroutine.Parse(bytes.NewReader(block.Bytes()))
+		if err != nil || len(gs) != 1 {
+			return nil
+		}
+		g := gs[0]
+		if f.probe {
+			f.probe = false
+			if g.Labels[probeKey] != probeValue {
+				return errNoTracebackLabels
+			}
+			return nil
+		}
+		if !f.match(g) {
+			return nil
+		}
+		if f.groups != nil {
+			name := f.group(g.Labels)
+			if f.groups[name] == nil {
+				f.groups[name] = make(map[string]int)
+			}
+			f.groups[name][g.State]++
+			return nil
+		}
+		n++
+		block.WriteByte('\n')
+		_, err = block.WriteTo(w)
+		return err
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		if f.opts.Limit > 0 && n >= f.opts.Limit {
+			return nil
+		}
+		if len(s.Bytes()) == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+		block.Write(s.Bytes())
+		block.WriteByte('\n')
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if f.opts.Limit > 0 && n >= f.opts.Limit {
+		return nil
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return f.writeGroups(w)
+}
+
+// writeProfileTo is like writeTo but reads a debug=1 dump from r, which
+// has labels on all runtimes but aggregates the goroutines by stack.
+func (f *stackFilter) writeProfileTo(w io.Writer, r io.Reader) error {
+	if f.opts.State != "" || f.opts.MinWait > 0 || len(f.ids) > 0 {
+		return fmt.Errorf("filtering by state, wait time or ID along with labels requires Go 1.27 or later, or GODEBUG=tracebacklabels=1 with Go 1.26")
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	// Skip the header, e.g. "goroutine profile: total 12".
+	_, stacks, _ := strings.Cut(string(b), "\n")
+	var n int
+	for _, block := range strings.Split(stacks, "\n\n") {
+		if f.opts.Limit > 0 && n >= f.opts.Limit {
+			return nil
+		}
+		count, labels, funcs := parseProfileBlock(block)
+		if count == 0 || !f.matchLabels(labels) {
+			continue
+		}
+		if f.fn != nil && !anyMatch(f.fn, funcs) {
+			continue
+		}
+		if f.groups != nil {
+			name := f.group(labels)
+			if f.groups[name] == nil {
+				f.groups[name] = make(map[string]int)
+			}
+			// The state is unknown.
+			f.groups[name][""] += count
+			continue
+		}
+		n++
+		if _, err := fmt.Fprintf(w, "%s\n\n", strings.TrimSpace(block)); err != nil {
+			return err
+		}
+	}
+	return f.writeGroups(w)
+}
+
+// parseProfileBlock parses a stack of a debug=1 goroutine dump, such as:
+//
+//	2 @ 0x47d82a 0x480985 0x4e141d 0x4835c1
+//	# labels: {"tenant":"acme"}
+//	#	0x480984	time.Sleep+0x164	/usr/local/go/src/runtime/time.go:368
+func parseProfileBlock(block string) (count int, labels map[string]string, funcs []string) {
+	for i, line := range strings.Split(strings.TrimSpace(block), "\n") {
+		if i == 0 {
+			n, _, _ := strings.Cut(line, " @ ")
+			count, _ = strconv.Atoi(n)
+			continue
+		}
+		if l := strings.TrimPrefix(line, "# labels: "); l != line {
+			json.Unmarshal([]byte(l), &labels)
+			continue
+		}
+		if fields := strings.Split(line, "\t"); len(fields) >= 3 && fields[0] == "#" {
+			fn, _, _ := strings.Cut(fields[2], "+0x")
+			funcs = append(funcs, fn)
+		}
+	}
+	return count, labels, funcs
+}
+
+func anyMatch(re *regexp.Regexp, ss []string) bool {
+	for _, s := range ss {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGroups writes the number of goroutines of each group, most
+// frequent first, along with their states if known.
+func (f *stackFilter) writeGroups(w io.Writer) error {
+	if f.groups == nil {
+		return nil
+	}
+	totals := make(map[string]int, len(f.groups))
+	names := make([]string, 0, len(f.groups))
+	for name, states := range f.groups {
+		for _, n := range states {
+			totals[name] += n
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if totals[names[i]] != totals[names[j]] {
+			return totals[names[i]] > totals[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		states := f.groups[name]
+		fmt.Fprintf(w, "%s: %d", name, totals[name])
+		if _, unknown := states[""]; !unknown {
+			fmt.Fprintf(w, " (%s)", formatCounts(states))
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func filteredStackTrace(r io.Reader, w io.Writer) error {
+	var opts internal.StackOptions
+	if err := json.NewDecoder(r).Decode(&opts); err != nil {
+		return err
+	}
+	f, err := newStackFilter(opts)
+	if err != nil {
+		return err
+	}
+	f.probe = opts.NeedLabels()
+	dump := goroutineDump(2, f.probe)
+	err = f.writeTo(w, dump)
+	dump.Close()
+	if err == errNoTracebackLabels {
+		if opts.PerGoroutine {
+			return fmt.Errorf("%v, writing goroutines one by one along with labels requires Go 1.27 or later, or GODEBUG=tracebacklabels=1 with Go 1.26", err)
+		}
+		dump = goroutineDump(1, false)
+		defer dump.Close()
+		return f.writeProfileTo(w, dump)
+	}
+	return err
+}
+
+// goroutineDump returns a reader of the goroutine dump at the given debug
+// level, written by a goroutine with the probe label if probe is set.
+func goroutineDump(debug int, probe bool) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		write := func(context.Context) {
+			pw.CloseWithError(pprof.Lookup("goroutine").WriteTo(pw, debug))
+		}
+		if probe {
+			pprof.Do(context.Background(), pprof.Labels(probeKey, probeValue), write)
+		} else {
+			write(context.Background())
+		}
+	}()
+	return pr
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+const testDump = `goroutine 1 [running]:
+main.main()
+	/src/main.go:10 +0x1d
+
+goroutine 7 [chan receive, 5 minutes]:
+main.worker(0xc000010000)
+	/src/worker.go:20 +0x2a
+created by main.main in goroutine 1
+	/src/main.go:8 +0x3b
+
+goroutine 9 [chan receive]:
+main.(*pool).wait(0xc000020000)
+	/src/pool.go:30 +0x4c
+created by main.main in goroutine 1
+	/src/main.go:9 +0x5d
+
+`
+
+func TestStackFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts internal.StackOptions
+		want []string
+	}{
+		{"none", internal.StackOptions{}, []string{"goroutine 1 ", "goroutine 7 ", "goroutine 9 "}},
+		{"state", internal.StackOptions{State: "chan receive"}, []string{"goroutine 7 ", "goroutine 9 "}},
+		{"func", internal.StackOptions{Func: `\(\*pool\)`}, []string{"goroutine 9 "}},
+		{"creator", internal.StackOptions{Func: `^main\.main$`}, []string{"goroutine 1 ", "goroutine 7 ", "goroutine 9 "}},
+		{"min-wait", internal.StackOptions{MinWait: time.Minute}, []string{"goroutine 7 "}},
+		{"id", internal.StackOptions{IDs: []int64{1, 9}}, []string{"goroutine 1 ", "goroutine 9 "}},
+		{"limit", internal.StackOptions{State: "chan receive", Limit: 1}, []string{"goroutine 7 "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newStackFilter(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := f.writeTo(&buf, strings.NewReader(testDump)); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(line, "goroutine ") {
+					got = append(got, line[:strings.Index(line, "[")])
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got goroutines %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+const testLabeledDump = `goroutine 3 [running] {gops: stack}:
+runtime/pprof.writeGoroutineStacks(...)
+	/go/src/runtime/pprof/pprof.go:816 +0x69
+
+goroutine 7 [chan receive] {kind: read, tenant: acme}:
+main.worker(0xc000010000)
+	/src/worker.go:20 +0x2a
+
+goroutine 8 [select] {tenant: acme}:
+main.worker(0xc000010000)
+	/src/worker.go:22 +0x2a
+
+goroutine 9 [chan receive] {tenant: "globex inc"}:
+main.worker(0xc000010000)
+	/src/worker.go:20 +0x2a
+
+goroutine 10 [select]:
+main.main()
+	/src/main.go:10 +0x1d
+
+`
+
+func TestStackFilterLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		opts internal.StackOptions
+		want string
+	}{
+		{"value", internal.StackOptions{Labels: []string{"tenant=acme"}}, "goroutine 7,goroutine 8"},
+		{"key", internal.StackOptions{Labels: []string{"kind"}}, "goroutine 7"},
+		{"quoted", internal.StackOptions{Labels: []string{"tenant=globex inc"}}, "goroutine 9"},
+		{"group", internal.StackOptions{GroupBy: "tenant"},
+			"tenant=acme: 2 (chan receive 1, select 1)\ntenant unset: 1 (select 1)\ntenant=globex inc: 1 (chan receive 1)"},
+		{"group-state", internal.StackOptions{GroupBy: "tenant", State: "select"},
+			"tenant unset: 1 (select 1)\ntenant=acme: 1 (select 1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newStackFilter(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.probe = true
+			var buf bytes.Buffer
+			if err := f.writeTo(&buf, strings.NewReader(testLabeledDump)); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				switch {
+				case strings.HasPrefix(line, "goroutine "):
+					got = append(got, line[:strings.Index(line, " [")])
+				case strings.HasPrefix(line, "tenant"):
+					got = append(got, line)
+				}
+			}
+			sep := ","
+			if tt.opts.GroupBy != "" {
+				sep = "\n"
+			}
+			if strings.Join(got, sep) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, sep), tt.want)
+			}
+		})
+	}
+}
+
+func TestStackFilterNoTracebackLabels(t *testing.T) {
+	f, err := newStackFilter(internal.StackOptions{GroupBy: "tenant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.probe = true
+	if err := f.writeTo(io.Discard, strings.NewReader(testDump)); err != errNoTracebackLabels {
+		t.Errorf("got error %v; want %v", err, errNoTracebackLabels)
+	}
+}
+
+const testProfileDump = `goroutine profile: total 4
+2 @ 0x47d82a 0x480985 0x4e141d 0x4835c1
+# labels: {"tenant":"acme"}
+#	0x480984	time.Sleep+0x164	/go/src/runtime/time.go:368
+#	0x4e141c	main.worker+0x1c	/src/worker.go:12
+
+1 @ 0x47d82a 0x4e141d 0x4835c1
+# labels: {"tenant":"globex"}
+#	0x4e141c	main.poll+0x1c	/src/poll.go:12
+
+1 @ 0x47d82a 0x4e141d 0x4835c1
+#	0x4e141c	main.main+0x1c	/src/main.go:12
+
+`
+
+func TestStackFilterProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		opts internal.StackOptions
+		want string
+	}{
+		{"label", internal.StackOptions{Labels: []string{"tenant"}, Func: `^main\.worker$`}, "2 @ 0x47d82a 0x480985 0x4e141d 0x4835c1"},
+		{"group", internal.StackOptions{GroupBy: "tenant"}, "tenant=acme: 2\ntenant unset: 1\ntenant=globex: 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newStackFilter(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := f.writeProfileTo(&buf, strings.NewReader(testProfileDump)); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if !strings.HasPrefix(line, "#") && line != "" {
+					got = append(got, line)
+				}
+			}
+			if strings.Join(got, "\n") != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	f, _ := newStackFilter(internal.StackOptions{Labels: []string{"tenant"}, State: "select"})
+	if err := f.writeProfileTo(io.Discard, strings.NewReader(testProfileDump)); err == nil {
+		t.Error("got no error filtering by state")
+	}
+}
+
+func TestFilteredStackTraceLabels(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	started := make(chan struct{})
+	pprof.Do(context.Background(), pprof.Labels("gops_test", "labeled"), func(context.Context) {
+		go func() {
+			close(started)
+			<-done
+		}()
+	})
+	<-started
+
+	var buf bytes.Buffer
+	if err := filteredStackTrace(strings.NewReader(`{"GroupBy": "gops_test"}`), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "gops_test=labeled: 1") {
+		t.Errorf("got:\n%s\nwant a goroutine labeled gops_test=labeled", buf.String())
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+)
+
+// startTime approximates when the process started.
+var startTime = time.Now()
+
+func writeStats(w io.Writer) {
+	fmt.Fprintf(w, "goroutines: %v\n", runtime.NumGoroutine())
+	fmt.Fprintf(w, "OS threads: %v\n", pprof.Lookup("threadcreate").Count())
+	fmt.Fprintf(w, "GOMAXPROCS: %v\n", runtime.GOMAXPROCS(0))
+	fmt.Fprintf(w, "num CPU: %v\n", runtime.NumCPU())
+	if states, ok := goroutineStates(); ok {
+		fmt.Fprintf(w, "goroutines by state: %v\n", states)
+	}
+	fmt.Fprintf(w, "cgo calls: %v\n", runtime.NumCgoCall())
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	fmt.Fprintf(w, "num GC: %v\n", gc.NumGC)
+	fmt.Fprintf(w, "GC percent: %v\n", gcPercent())
+	fmt.Fprintf(w, "memory limit: %v\n", memoryLimit())
+	fmt.Fprintf(w, "uptime: %v\n", time.Since(startTime).Round(time.Second))
+	if cpu, mem, ok := cgroupLimits(); ok {
+		quota := "none"
+		if cpu > 0 {
+			quota = fmt.Sprintf("%.2f CPUs", cpu)
+		}
+		limit := "none"
+		if mem > 0 {
+			limit = formatBytes(mem)
+		}
+		fmt.Fprintf(w, "cgroup CPU quota: %v\n", quota)
+		fmt.Fprintf(w, "cgroup memory limit: %v\n", limit)
+	}
+}
+
+// formatCounts formats counts such as "chan receive 5, running 1", most
+// frequent first.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for i, k := range keys {
+		keys[i] = fmt.Sprintf("%s %d", k, counts[k])
+	}
+	return strings.Join(keys, ", ")
+}
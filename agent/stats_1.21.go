@@ -0,0 +1,32 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package agent
+
+import (
+	"math"
+	"runtime/metrics"
+	"strconv"
+)
+
+func gcPercent() string {
+	s := []metrics.Sample{{Name: "/gc/gogc:percent"}}
+	metrics.Read(s)
+	if p := s[0].Value.Uint64(); p != math.MaxUint64 {
+		return strconv.FormatUint(p, 10)
+	}
+	return "off"
+}
+
+func memoryLimit() string {
+	s := []metrics.Sample{{Name: "/gc/gomemlimit:bytes"}}
+	metrics.Read(s)
+	if l := s[0].Value.Uint64(); l != math.MaxInt64 {
+		return formatBytes(l)
+	}
+	return "none"
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.26
+// +build go1.26
+
+package agent
+
+import "runtime/metrics"
+
+// goroutineStates returns the approximate number of goroutines in each
+// scheduling state, most frequent first, read without stopping the world.
+func goroutineStates() (string, bool) {
+	states := []string{"running", "runnable", "waiting", "not-in-go"}
+	s := make([]metrics.Sample, len(states))
+	for i, state := range states {
+		s[i].Name = "/sched/goroutines/" + state + ":goroutines"
+	}
+	metrics.Read(s)
+	counts := make(map[string]int)
+	for i, state := range states {
+		if s[i].Value.Kind() == metrics.KindUint64 {
+			counts[state] = int(s[i].Value.Uint64())
+		}
+	}
+	return formatCounts(counts), true
+}
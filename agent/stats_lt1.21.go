@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.21
+// +build !go1.21
+
+package agent
+
+func gcPercent() string {
+	// It could only be read by setting it, which would race with the
+	// program setting it.
+	return "unknown"
+}
+
+func memoryLimit() string {
+	return "unknown"
+}
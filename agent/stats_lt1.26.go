@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.26
+// +build !go1.26
+
+package agent
+
+// goroutineStates isn't supported before Go 1.26, as the goroutines could
+// only be counted by state from a dump, stopping the world at every call.
+func goroutineStates() (string, bool) {
+	return "", false
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+
+	"github.com/google/gops/internal"
+)
+
+func symbolize(r io.Reader, w io.Writer) error {
+	var pcs []uint64
+	if err := json.NewDecoder(r).Decode(&pcs); err != nil {
+		return err
+	}
+	frames := make([][]internal.Frame, len(pcs))
+	for i, pc := range pcs {
+		frames[i] = pcFrames(uintptr(pc))
+	}
+	return json.NewEncoder(w).Encode(frames)
+}
+
+// pcFrames returns the frames of the instruction at pc, including the
+// functions inlined there, innermost first. It returns nil if pc is not in
+// Go code.
+func pcFrames(pc uintptr) []internal.Frame {
+	// CallersFrames expects return addresses, which follow the call
+	// instruction.
+	fs := runtime.CallersFrames([]uintptr{pc + 1})
+	var frames []internal.Frame
+	for {
+		f, more := fs.Next()
+		if f.Function != "" {
+			frames = append(frames, internal.Frame{Func: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			return frames
+		}
+	}
+}
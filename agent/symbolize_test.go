@@ -0,0 +1,45 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func TestSymbolize(t *testing.T) {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	want, _ := runtime.CallersFrames(pcs).Next()
+
+	in := strings.NewReader(`[` + strconv.FormatUint(uint64(want.PC), 10) + `, 1]`)
+	var out bytes.Buffer
+	if err := symbolize(in, &out); err != nil {
+		t.Fatal(err)
+	}
+	var frames [][]internal.Frame
+	if err := json.Unmarshal(out.Bytes(), &frames); err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("got %d results; want 2", len(frames))
+	}
+	if len(frames[0]) != 1 {
+		t.Fatalf("got frames %v; want 1", frames[0])
+	}
+	got := frames[0][0]
+	if got.Func != want.Function || got.File != want.File || got.Line != want.Line {
+		t.Errorf("got frame %+v; want %v at %v:%v", got, want.Function, want.File, want.Line)
+	}
+	if len(frames[1]) != 0 {
+		t.Errorf("got frames %v for an invalid pc; want none", frames[1])
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"time"
+)
+
+// TriggerOptions configures the automatic capture of a heap profile, a
+// goroutine dump and memory stats when the process crosses one of the
+// thresholds. Zero thresholds are disabled.
+type TriggerOptions struct {
+	// HeapAlloc is the threshold of bytes of allocated heap objects.
+	HeapAlloc uint64
+
+	// Goroutines is the threshold of number of goroutines.
+	Goroutines int
+
+	// GCCPUFraction is the threshold of fraction of CPU time used by the
+	// GC since the program started.
+	GCCPUFraction float64
+
+	// RSSGrowthRate is the threshold of growth of the resident set size in
+	// bytes per second between two checks. It is only supported on Linux.
+	RSSGrowthRate uint64
+
+	// CheckInterval is the time between two checks of the thresholds.
+	// Defaults to 1 second. The checks read runtime/metrics, except before
+	// Go 1.20, where they read runtime.MemStats, stopping the world
+	// briefly.
+	CheckInterval time.Duration
+
+	// Cooldown is the minimum time between two captures. Defaults to
+	// 5 minutes.
+	Cooldown time.Duration
+
+	// Keep is the number of captures kept, older ones being removed.
+	// Defaults to 10.
+	Keep int
+
+	// Dir is the directory the captures are stored in. Defaults to
+	// captures/<pid> in the config directory, where the directories of
+	// the processes that are gone are removed.
+	Dir string
+}
+
+// triggerSample is the state of the process checked against the thresholds.
+type triggerSample struct {
+	time          time.Time
+	heapAlloc     uint64
+	goroutines    int
+	gcCPUFraction float64
+	rss           uint64 // 0 if unknown
+}
+
+// check returns why s crossed a threshold since prev, or "" if it didn't.
+func (o *TriggerOptions) check(s, prev triggerSample) string {
+	switch {
+	case o.HeapAlloc > 0 && s.heapAlloc > o.HeapAlloc:
+		return fmt.Sprintf("heap-alloc %v above %v", formatBytes(s.heapAlloc), formatBytes(o.HeapAlloc))
+	case o.Goroutines > 0 && s.goroutines > o.Goroutines:
+		return fmt.Sprintf("goroutines %v above %v", s.goroutines, o.Goroutines)
+	case o.GCCPUFraction > 0 && s.gcCPUFraction > o.GCCPUFraction:
+		return fmt.Sprintf("gc-cpu-fraction %v above %v", s.gcCPUFraction, o.GCCPUFraction)
+	case o.RSSGrowthRate > 0 && s.rss > prev.rss && prev.rss > 0:
+		rate := uint64(float64(s.rss-prev.rss) / s.time.Sub(prev.time).Seconds())
+		if rate > o.RSSGrowthRate {
+			return fmt.Sprintf("rss growth %v/s above %v/s", formatBytes(rate), formatBytes(o.RSSGrowthRate))
+		}
+	}
+	return ""
+}
+
+var (
+	captures     *history
+	stopCaptures func()
+)
+
+func startTriggers(opts TriggerOptions, gopsdir string) error {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = time.Second
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 5 * time.Minute
+	}
+	if opts.Keep <= 0 {
+		opts.Keep = 10
+	}
+	if opts.Dir == "" {
+		dir := filepath.Join(gopsdir, "captures")
+		removeDeadPIDDirs(dir)
+		opts.Dir = filepath.Join(dir, strconv.Itoa(os.Getpid()))
+	}
+	h, err := newHistory(opts.Dir, opts.Keep)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(opts.CheckInterval)
+		defer t.Stop()
+		var prev triggerSample
+		var last time.Time
+		for {
+			select {
+			case <-t.C:
+			case <-done:
+				return
+			}
+			s := sampleTriggers()
+			reason := opts.check(s, prev)
+			prev = s
+			if reason == "" || s.time.Sub(last) < opts.Cooldown {
+				continue
+			}
+			last = s.time
+			if err := captureDiagnostics(h, s.time, reason); err != nil {
+				fmt.Fprintf(os.Stderr, "gops: triggered capture: %v\n", err)
+			}
+		}
+	}()
+	captures = h
+	stopCaptures = func() {
+		close(done)
+		<-stopped
+	}
+	return nil
+}
+
+func stopTriggers() {
+	if stopCaptures != nil {
+		stopCaptures()
+		stopCaptures = nil
+		captures = nil
+	}
+}
+
+func sampleTriggers() triggerSample {
+	heapAlloc, gcCPUFraction := readTriggerMemory()
+	rss, _ := readRSS()
+	return triggerSample{
+		time:          time.Now(),
+		heapAlloc:     heapAlloc,
+		goroutines:    runtime.NumGoroutine(),
+		gcCPUFraction: gcCPUFraction,
+		rss:           rss,
+	}
+}
+
+// captureDiagnostics writes why the capture happened, a heap profile, a
+// goroutine dump and memory stats.
+func captureDiagnostics(h *history, now time.Time, reason string) error {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	files := []struct {
+		kind  string
+		write func(w io.Writer) error
+	}{
+		{"reason.txt", func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, reason)
+			return err
+		}},
+		{"heap.pprof", pprof.WriteHeapProfile},
+		{"goroutines.txt", func(w io.Writer) error {
+			return pprof.Lookup("goroutine").WriteTo(w, 2)
+		}},
+		{"memstats.txt", func(w io.Writer) error {
+			writeMemStats(w, &m)
+			return nil
+		}},
+	}
+	for _, file := range files {
+		f, err := h.create(now, file.kind)
+		if err != nil {
+			return err
+		}
+		err = file.write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return h.prune()
+}
+
+var errNoTriggers = errors.New("triggered captures are not enabled")
+
+func listCaptures(_ io.Reader, w io.Writer) error {
+	mu.Lock()
+	h := captures
+	mu.Unlock()
+	if h == nil {
+		return errNoTriggers
+	}
+	files, err := h.list()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(files)
+}
+
+func fetchCapture(r io.Reader, w io.Writer) error {
+	var name string
+	if err := json.NewDecoder(r).Decode(&name); err != nil {
+		return err
+	}
+	mu.Lock()
+	h := captures
+	mu.Unlock()
+	if h == nil {
+		return errNoTriggers
+	}
+	return h.writeFile(w, name)
+}
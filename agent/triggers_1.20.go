@@ -0,0 +1,27 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.20
+// +build go1.20
+
+package agent
+
+import "runtime/metrics"
+
+// readTriggerMemory returns the bytes of allocated heap objects and the
+// fraction of CPU time used by the GC since the program started, without
+// stopping the world.
+func readTriggerMemory() (heapAlloc uint64, gcCPUFraction float64) {
+	s := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/cpu/classes/gc/total:cpu-seconds"},
+		{Name: "/cpu/classes/total:cpu-seconds"},
+	}
+	metrics.Read(s)
+	heapAlloc = s[0].Value.Uint64()
+	if total := s[2].Value.Float64(); total > 0 {
+		gcCPUFraction = s[1].Value.Float64() / total
+	}
+	return heapAlloc, gcCPUFraction
+}
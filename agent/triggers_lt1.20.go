@@ -0,0 +1,20 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.20
+// +build !go1.20
+
+package agent
+
+import "runtime"
+
+// readTriggerMemory returns the bytes of allocated heap objects and the
+// fraction of CPU time used by the GC since the program started. The GC
+// CPU time is only in runtime/metrics from Go 1.20, so it reads
+// runtime.MemStats, which stops the world.
+func readTriggerMemory() (heapAlloc uint64, gcCPUFraction float64) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc, m.GCCPUFraction
+}
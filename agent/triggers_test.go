@@ -0,0 +1,37 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTriggerCheck(t *testing.T) {
+	now := time.Now()
+	prev := triggerSample{time: now.Add(-time.Second), heapAlloc: 10, goroutines: 10, rss: 1000}
+	tests := []struct {
+		name string
+		opts TriggerOptions
+		s    triggerSample
+		want string
+	}{
+		{"disabled", TriggerOptions{}, triggerSample{time: now, heapAlloc: 1 << 30, goroutines: 1e6, rss: 1 << 30}, ""},
+		{"heap-alloc", TriggerOptions{HeapAlloc: 1024}, triggerSample{time: now, heapAlloc: 2048}, "heap-alloc 2.00KB (2048 bytes) above 1.00KB (1024 bytes)"},
+		{"heap-alloc below", TriggerOptions{HeapAlloc: 1024}, triggerSample{time: now, heapAlloc: 1024}, ""},
+		{"goroutines", TriggerOptions{Goroutines: 100}, triggerSample{time: now, goroutines: 101}, "goroutines 101 above 100"},
+		{"gc-cpu-fraction", TriggerOptions{GCCPUFraction: 0.25}, triggerSample{time: now, gcCPUFraction: 0.5}, "gc-cpu-fraction 0.5 above 0.25"},
+		{"rss growth", TriggerOptions{RSSGrowthRate: 100}, triggerSample{time: now, rss: 1200}, "rss growth 200 bytes/s above 100 bytes/s"},
+		{"rss growth below", TriggerOptions{RSSGrowthRate: 100}, triggerSample{time: now, rss: 1050}, ""},
+		{"rss unknown", TriggerOptions{RSSGrowthRate: 100}, triggerSample{time: now}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.check(tt.s, prev); got != tt.want {
+				t.Errorf("check() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+// Var is a variable of the application, such as a feature toggle or a
+// sampling rate, that can be read with `gops get` and changed with
+// `gops set` while the program runs. Its methods must be safe for
+// concurrent use. Var has the same methods as flag.Value.
+type Var interface {
+	String() string
+	Set(string) error
+}
+
+type appVar struct {
+	description string
+	v           Var
+}
+
+var (
+	appVarsMu sync.RWMutex
+	appVars   = make(map[string]appVar)
+)
+
+// RegisterVar registers v under name.
+func RegisterVar(name, description string, v Var) {
+	if v == nil {
+		panic("gops: nil variable " + name)
+	}
+	appVarsMu.Lock()
+	defer appVarsMu.Unlock()
+	internal.CheckName("variable", name, appVars)
+	appVars[name] = appVar{description: description, v: v}
+}
+
+// typedVar is a Var holding a value of type T.
+type typedVar[T any] struct {
+	mu       sync.RWMutex
+	value    T
+	parse    func(string) (T, error)
+	format   func(T) string
+	validate func(T) error
+}
+
+func (v *typedVar[T]) get() T {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.value
+}
+
+func (v *typedVar[T]) String() string {
+	return v.format(v.get())
+}
+
+// Set parses s and sets the variable to it, unless validation fails.
+func (v *typedVar[T]) Set(s string) error {
+	x, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	if v.validate != nil {
+		if err := v.validate(x); err != nil {
+			return err
+		}
+	}
+	v.mu.Lock()
+	v.value = x
+	v.mu.Unlock()
+	return nil
+}
+
+// BoolVar is a bool Var created with NewBool.
+type BoolVar struct {
+	typedVar[bool]
+}
+
+// NewBool registers a bool variable with the given initial value. If
+// validate is not nil, the new values set with `gops set` must pass it.
+func NewBool(name, description string, value bool, validate func(bool) error) *BoolVar {
+	v := &BoolVar{typedVar[bool]{value: value, parse: strconv.ParseBool, format: strconv.FormatBool, validate: validate}}
+	RegisterVar(name, description, v)
+	return v
+}
+
+// Value returns the current value of v.
+func (v *BoolVar) Value() bool { return v.get() }
+
+// IntVar is an int64 Var created with NewInt.
+type IntVar struct {
+	typedVar[int64]
+}
+
+// NewInt registers an int64 variable with the given initial value. If
+// validate is not nil, the new values set with `gops set` must pass it.
+func NewInt(name, description string, value int64, validate func(int64) error) *IntVar {
+	parse := func(s string) (int64, error) { return strconv.ParseInt(s, 0, 64) }
+	format := func(i int64) string { return strconv.FormatInt(i, 10) }
+	v := &IntVar{typedVar[int64]{value: value, parse: parse, format: format, validate: validate}}
+	RegisterVar(name, description, v)
+	return v
+}
+
+// Value returns the current value of v.
+func (v *IntVar) Value() int64 { return v.get() }
+
+// DurationVar is a time.Duration Var created with NewDuration.
+type DurationVar struct {
+	typedVar[time.Duration]
+}
+
+// NewDuration registers a time.Duration variable with the given initial
+// value, set in the format of time.ParseDuration. If validate is not nil,
+// the new values set with `gops set` must pass it.
+func NewDuration(name, description string, value time.Duration, validate func(time.Duration) error) *DurationVar {
+	format := func(d time.Duration) string { return d.String() }
+	v := &DurationVar{typedVar[time.Duration]{value: value, parse: time.ParseDuration, format: format, validate: validate}}
+	RegisterVar(name, description, v)
+	return v
+}
+
+// Value returns the current value of v.
+func (v *DurationVar) Value() time.Duration { return v.get() }
+
+// StringVar is a string Var created with NewString.
+type StringVar struct {
+	typedVar[string]
+}
+
+// NewString registers a string variable with the given initial value. If
+// validate is not nil, the new values set with `gops set` must pass it.
+func NewString(name, description string, value string, validate func(string) error) *StringVar {
+	parse := func(s string) (string, error) { return s, nil }
+	format := func(s string) string { return s }
+	v := &StringVar{typedVar[string]{value: value, parse: parse, format: format, validate: validate}}
+	RegisterVar(name, description, v)
+	return v
+}
+
+// Value returns the current value of v.
+func (v *StringVar) Value() string { return v.get() }
+
+func getVars(r io.Reader, w io.Writer) error {
+	var names []string
+	if err := json.NewDecoder(r).Decode(&names); err != nil {
+		return err
+	}
+	appVarsMu.RLock()
+	defer appVarsMu.RUnlock()
+	if len(names) == 0 {
+		for name := range appVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	list := make([]internal.Var, 0, len(names))
+	for _, name := range names {
+		v, ok := appVars[name]
+		if !ok {
+			return fmt.Errorf("no variable %q", name)
+		}
+		list = append(list, internal.Var{Name: name, Value: v.v.String(), Description: v.description})
+	}
+	return json.NewEncoder(w).Encode(list)
+}
+
+func setVar(r io.Reader, w io.Writer) error {
+	var set internal.Var
+	if err := json.NewDecoder(r).Decode(&set); err != nil {
+		return err
+	}
+	// Held until the new value is read, for concurrent changes not to be
+	// interleaved.
+	appVarsMu.Lock()
+	defer appVarsMu.Unlock()
+	v, ok := appVars[set.Name]
+	if !ok {
+		return fmt.Errorf("no variable %q", set.Name)
+	}
+	change := internal.VarChange{Previous: v.v.String()}
+	if err := v.v.Set(set.Value); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %v", set.Value, set.Name, err)
+	}
+	change.Value = v.v.String()
+	return json.NewEncoder(w).Encode(change)
+}
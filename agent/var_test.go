@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestAppVars(t *testing.T) {
+	enabled := NewBool("test-enabled", "Enables the feature.", false, nil)
+	rate := NewInt("test-rate", "", 10, func(i int64) error {
+		if i < 0 || i > 100 {
+			return errors.New("out of range")
+		}
+		return nil
+	})
+	timeout := NewDuration("test-timeout", "", time.Second, nil)
+	mode := NewString("test-mode", "", "fast", nil)
+
+	get := func(names ...string) ([]internal.Var, error) {
+		b, _ := json.Marshal(names)
+		var buf bytes.Buffer
+		if err := getVars(bytes.NewReader(b), &buf); err != nil {
+			return nil, err
+		}
+		var vs []internal.Var
+		err := json.Unmarshal(buf.Bytes(), &vs)
+		return vs, err
+	}
+	set := func(name, value string) (internal.VarChange, error) {
+		b, _ := json.Marshal(internal.Var{Name: name, Value: value})
+		var buf bytes.Buffer
+		if err := setVar(bytes.NewReader(b), &buf); err != nil {
+			return internal.VarChange{}, err
+		}
+		var change internal.VarChange
+		err := json.Unmarshal(buf.Bytes(), &change)
+		return change, err
+	}
+
+	vs, err := get("test-enabled", "test-rate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []internal.Var{
+		{Name: "test-enabled", Value: "false", Description: "Enables the feature."},
+		{Name: "test-rate", Value: "10"},
+	}
+	if len(vs) != 2 || vs[0] != want[0] || vs[1] != want[1] {
+		t.Errorf("got vars %v; want %v", vs, want)
+	}
+
+	for _, tt := range []struct {
+		name, value string
+		want        internal.VarChange
+	}{
+		{"test-enabled", "1", internal.VarChange{Previous: "false", Value: "true"}},
+		{"test-rate", "0x2a", internal.VarChange{Previous: "10", Value: "42"}},
+		{"test-timeout", "90s", internal.VarChange{Previous: "1s", Value: "1m30s"}},
+		{"test-mode", "safe", internal.VarChange{Previous: "fast", Value: "safe"}},
+	} {
+		change, err := set(tt.name, tt.value)
+		if err != nil || change != tt.want {
+			t.Errorf("set %s to %q = %+v, %v; want %+v", tt.name, tt.value, change, err, tt.want)
+		}
+	}
+	if !enabled.Value() || rate.Value() != 42 || timeout.Value() != 90*time.Second || mode.Value() != "safe" {
+		t.Errorf("got values %v, %v, %v, %q", enabled.Value(), rate.Value(), timeout.Value(), mode.Value())
+	}
+
+	for _, tt := range []struct{ name, value string }{
+		{"test-rate", "101"},
+		{"test-rate", "ten"},
+		{"test-enabled", "maybe"},
+		{"test-missing", "1"},
+	} {
+		if _, err := set(tt.name, tt.value); err == nil {
+			t.Errorf("set %s to %q succeeded; want an error", tt.name, tt.value)
+		}
+	}
+	if rate.Value() != 42 {
+		t.Errorf("rate changed to %d by an invalid value", rate.Value())
+	}
+	if _, err := get("test-missing"); err == nil {
+		t.Error("got a missing variable")
+	}
+}
+
+func TestRegisterVarTwice(t *testing.T) {
+	NewBool("test-twice-var", "", false, nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a variable twice didn't panic")
+		}
+	}()
+	NewString("test-twice-var", "", "", nil)
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/google/gops/internal"
+)
+
+func vars(r io.Reader, w io.Writer) error {
+	var names []string
+	if err := json.NewDecoder(r).Decode(&names); err != nil {
+		return err
+	}
+	if internal.Expvars == nil {
+		return errors.New("expvar support isn't enabled: import github.com/google/gops/agent/expvars")
+	}
+	vs, err := internal.Expvars(names)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(vs)
+}
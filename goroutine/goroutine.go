@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package goroutine parses the goroutine dumps printed by `gops stack`,
+// runtime/pprof's goroutine profile with debug=2 and unrecovered panics.
+package goroutine
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goroutine is a goroutine of a dump.
+type Goroutine struct {
+	// ID is the goroutine ID.
+	ID int64 `json:"id"`
+
+	// State is the state of the goroutine, such as "running" or
+	// "chan receive".
+	State string `json:"state"`
+
+	// Wait is approximately how long the goroutine has been blocked. The
+	// runtime only reports it in minutes.
+	Wait time.Duration `json:"wait,omitempty"`
+
+	// LockedToThread reports whether the goroutine is locked to its
+	// OS thread.
+	LockedToThread bool `json:"locked_to_thread,omitempty"`
+
+	// Labels are the pprof labels of the goroutine. Only recent runtimes
+	// include them in dumps.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Frames is the stack of the goroutine, innermost frame first.
+	Frames []Frame `json:"frames"`
+
+	// FramesElided reports whether the runtime omitted frames from the
+	// middle of a deep stack.
+	FramesElided bool `json:"frames_elided,omitempty"`
+
+	// CreatedBy is the go statement that created the goroutine, if any.
+	CreatedBy *Frame `json:"created_by,omitempty"`
+
+	// CreatorID is the ID of the goroutine that created the goroutine,
+	// if known.
+	CreatorID int64 `json:"creator_id,omitempty"`
+}
+
+// Frame is a stack frame.
+type Frame struct {
+	// Func is the fully qualified function name, such as
+	// "net/http.(*Server).Serve".
+	Func string `json:"func"`
+
+	// Args is the argument list as printed by the runtime, such as
+	// "0xc000010000, {0x1, 0x2}". Created-by frames have no arguments.
+	Args string `json:"args,omitempty"`
+
+	// File is the path of the source file.
+	File string `json:"file"`
+
+	// Line is the line number in File.
+	Line int `json:"line"`
+}
+
+// Parse parses the goroutines of a dump. Lines that don't belong to a
+// goroutine, such as panic messages, are ignored.
+func Parse(r io.Reader) ([]*Goroutine, error) {
+	var (
+		gs    []*Goroutine
+		g     *Goroutine
+		frame *Frame
+	)
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		switch {
+		case line == "":
+			g, frame = nil, nil
+		case strings.HasPrefix(line, "goroutine "):
+			if g = ParseHeader(line); g != nil {
+				gs = append(gs, g)
+			}
+			frame = nil
+		case g == nil:
+			// Not part of a goroutine.
+		case strings.HasPrefix(line, "\t"):
+			if frame != nil {
+				frame.File, frame.Line = parseFileLine(strings.TrimSpace(line))
+			}
+		case line == "...additional frames elided...":
+			g.FramesElided = true
+		case strings.HasPrefix(line, "created by "):
+			g.CreatedBy, g.CreatorID = parseCreatedBy(line)
+			frame = g.CreatedBy
+		default:
+			g.Frames = append(g.Frames, parseCall(line))
+			frame = &g.Frames[len(g.Frames)-1]
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return gs, nil
+}
+
+// ParseHeader parses a goroutine header line such as
+// "goroutine 7 [chan receive, 5 minutes]:". It returns nil if line isn't a
+// goroutine header.
+func ParseHeader(line string) *Goroutine {
+	rest := strings.TrimPrefix(line, "goroutine ")
+	if rest == line {
+		return nil
+	}
+	open := strings.Index(rest, " [")
+	end := strings.Index(rest, "]")
+	if open < 0 || end < open {
+		return nil
+	}
+	// GOTRACEBACK=system and above add "gp=... m=..." after the ID.
+	fields := strings.Fields(rest[:open])
+	if len(fields) == 0 {
+		return nil
+	}
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	g := &Goroutine{ID: id}
+	attrs := strings.Split(rest[open+2:end], ", ")
+	g.State = attrs[0]
+	for _, attr := range attrs[1:] {
+		switch {
+		case attr == "locked to thread":
+			g.LockedToThread = true
+		case strings.HasSuffix(attr, " minutes"):
+			if m, err := strconv.Atoi(strings.TrimSuffix(attr, " minutes")); err == nil {
+				g.Wait = time.Duration(m) * time.Minute
+			}
+		}
+	}
+	labels := strings.TrimSpace(strings.TrimSuffix(rest[end+1:], ":"))
+	if strings.HasPrefix(labels, "{") && strings.HasSuffix(labels, "}") {
+		g.Labels = parseLabels(labels[1 : len(labels)-1])
+	}
+	return g
+}
+
+// parseLabels parses labels such as `tenant: acme, path: "/a b"`.
+func parseLabels(s string) map[string]string {
+	labels := make(map[string]string)
+	for s != "" {
+		k, rest, ok := labelString(s, ':')
+		if !ok {
+			break
+		}
+		v, rest, ok := labelString(strings.TrimPrefix(rest, " "), ',')
+		if !ok {
+			break
+		}
+		labels[k] = v
+		s = strings.TrimPrefix(rest, " ")
+	}
+	return labels
+}
+
+// labelString reads a possibly quoted label key or value from the start of s,
+// followed by sep or the end of s, and returns it along with what follows
+// sep.
+func labelString(s string, sep byte) (str, rest string, ok bool) {
+	if strings.HasPrefix(s, `"`) {
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", false
+		}
+		str, _ = strconv.Unquote(q)
+		rest = s[len(q):]
+		if rest != "" && rest[0] != sep {
+			return "", "", false
+		}
+	} else if i := strings.IndexByte(s, sep); i >= 0 {
+		str, rest = s[:i], s[i:]
+	} else {
+		str = s
+	}
+	return str, strings.TrimPrefix(rest, string(sep)), true
+}
+
+// parseCall parses a call line such as "main.(*T).run(0xc000010000, 0x1)".
+func parseCall(line string) Frame {
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return Frame{Func: line[:i], Args: line[i+1 : len(line)-1]}
+		}
+	}
+	return Frame{Func: line}
+}
+
+// parseCreatedBy parses a line such as "created by main.main in goroutine 1".
+func parseCreatedBy(line string) (*Frame, int64) {
+	fn := strings.TrimPrefix(line, "created by ")
+	var id int64
+	if i := strings.Index(fn, " in goroutine "); i >= 0 {
+		id, _ = strconv.ParseInt(fn[i+len(" in goroutine "):], 10, 64)
+		fn = fn[:i]
+	}
+	return &Frame{Func: fn}, id
+}
+
+// parseFileLine parses a location such as "/src/main.go:12 +0x1d".
+func parseFileLine(s string) (file string, line int) {
+	if i := strings.LastIndex(s, " +0x"); i >= 0 {
+		s = s[:i]
+	}
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0
+	}
+	return s[:i], line
+}
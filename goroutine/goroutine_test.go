@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goroutine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dump = `panic: boom
+
+goroutine 1 [running]:
+main.main()
+	/src/main.go:10 +0x1d
+
+goroutine 7 gp=0xc000007c00 m=nil [chan receive, 5 minutes, locked to thread] {tenant: acme, "request kind": "bulk, slow"}:
+main.(*worker).run(0xc000010000, {0x1, 0x2})
+	/src/worker.go:20 +0x2a
+...additional frames elided...
+created by main.main in goroutine 1
+	/src/main.go:8 +0x3b
+
+goroutine 9 [select]:
+main.wait()
+	/src/wait.go:30
+created by main.main
+	/src/main.go:9
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Goroutine{
+		{
+			ID:     1,
+			State:  "running",
+			Frames: []Frame{{Func: "main.main", File: "/src/main.go", Line: 10}},
+		},
+		{
+			ID:             7,
+			State:          "chan receive",
+			Wait:           5 * time.Minute,
+			LockedToThread: true,
+			Labels:         map[string]string{"tenant": "acme", "request kind": "bulk, slow"},
+			Frames: []Frame{
+				{Func: "main.(*worker).run", Args: "0xc000010000, {0x1, 0x2}", File: "/src/worker.go", Line: 20},
+			},
+			FramesElided: true,
+			CreatedBy:    &Frame{Func: "main.main", File: "/src/main.go", Line: 8},
+			CreatorID:    1,
+		},
+		{
+			ID:        9,
+			State:     "select",
+			Frames:    []Frame{{Func: "main.wait", File: "/src/wait.go", Line: 30}},
+			CreatedBy: &Frame{Func: "main.main", File: "/src/main.go", Line: 9},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d goroutines; want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("goroutine %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	for _, line := range []string{"main.main()", "goroutine x [running]:", "goroutine 1 running", "goroutine  [running]:"} {
+		if g := ParseHeader(line); g != nil {
+			t.Errorf("ParseHeader(%q) = %+v; want nil", line, g)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// Capabilities describes an agent, as returned by signal.Capabilities.
+type Capabilities struct {
+	// AgentVersion is the version of the gops module the agent was built
+	// from, or "unknown".
+	AgentVersion string
+
+	// GoVersion is the Go version the process was built with.
+	GoVersion string
+
+	// Commands are the signal bytes the agent supports.
+	Commands []int
+
+	// Options are the agent options enabled, such as "flight-recorder".
+	Options []string
+}
+
+// Supports reports whether the agent supports the command cmd.
+func (c *Capabilities) Supports(cmd byte) bool {
+	for _, s := range c.Commands {
+		if s == int(cmd) {
+			return true
+		}
+	}
+	return false
+}
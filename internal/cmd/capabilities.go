@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+// signalNames are the names of the commands of the signal package.
+var signalNames = map[byte]string{
+	signal.StackTrace:         "StackTrace",
+	signal.GC:                 "GC",
+	signal.MemStats:           "MemStats",
+	signal.Version:            "Version",
+	signal.HeapProfile:        "HeapProfile",
+	signal.CPUProfile:         "CPUProfile",
+	signal.Stats:              "Stats",
+	signal.BinaryDump:         "BinaryDump",
+	signal.Trace:              "Trace",
+	signal.SetGCPercent:       "SetGCPercent",
+	signal.FilteredStackTrace: "FilteredStackTrace",
+	signal.TraceSnapshot:      "TraceSnapshot",
+	signal.ListProfiles:       "ListProfiles",
+	signal.FetchProfile:       "FetchProfile",
+	signal.ListCaptures:       "ListCaptures",
+	signal.FetchCapture:       "FetchCapture",
+	signal.GCTrace:            "GCTrace",
+	signal.GCStats:            "GCStats",
+	signal.HeapDump:           "HeapDump",
+	signal.FileDescriptors:    "FileDescriptors",
+	signal.Symbolize:          "Symbolize",
+	signal.Vars:               "Vars",
+	signal.SampleStacks:       "SampleStacks",
+	signal.ListCommands:       "ListCommands",
+	signal.CallCommand:        "CallCommand",
+	signal.Capabilities:       "Capabilities",
+	signal.HealthCheck:        "HealthCheck",
+	signal.GetVars:            "GetVars",
+	signal.SetVar:             "SetVar",
+	signal.LogLevel:           "LogLevel",
+	signal.Logs:               "Logs",
+	signal.Requests:           "Requests",
+	signal.DBStats:            "DBStats",
+	signal.Coverage:           "Coverage",
+}
+
+func agentCapabilitiesCmd(addr net.TCPAddr, _ []string) error {
+	caps, err := capabilities(addr)
+	if err != nil {
+		return err
+	}
+	if caps == nil {
+		return &tooOldError{name: commandName}
+	}
+	printCapabilities(os.Stdout, caps)
+	return nil
+}
+
+func printCapabilities(w io.Writer, caps *internal.Capabilities) {
+	fmt.Fprintf(w, "agent version: %v\n", caps.AgentVersion)
+	fmt.Fprintf(w, "go version: %v\n", caps.GoVersion)
+	options := "none"
+	if len(caps.Options) > 0 {
+		options = strings.Join(caps.Options, ", ")
+	}
+	fmt.Fprintf(w, "options: %v\n", options)
+	var cmds []string
+	for _, c := range caps.Commands {
+		name, ok := signalNames[byte(c)]
+		if !ok {
+			// The agent is more recent than gops.
+			name = fmt.Sprintf("%#x", c)
+		}
+		cmds = append(cmds, name)
+	}
+	fmt.Fprintf(w, "commands: %v\n", strings.Join(cmds, ", "))
+}
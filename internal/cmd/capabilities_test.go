@@ -0,0 +1,83 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/agent"
+	_ "github.com/google/gops/agent/expvars"
+	"github.com/google/gops/signal"
+)
+
+func TestCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	if err := agent.Listen(agent.Options{Addr: "127.0.0.1:0", ConfigDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	addr := agentAddr(t, dir)
+
+	caps, err := capabilities(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps == nil || !caps.Supports(signal.StackTrace) || !caps.Supports(signal.Capabilities) {
+		t.Errorf("got capabilities %+v", caps)
+	}
+	if _, err := request(addr, signal.Vars, []string{"cmdline"}); err != nil {
+		t.Error(err)
+	}
+}
+
+// agentAddr returns the address of the agent started by the test, as
+// saved in the config dir.
+func agentAddr(t *testing.T, dir string) net.TCPAddr {
+	t.Helper()
+	port, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(os.Getpid())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(strings.TrimSpace(string(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: p}
+}
+
+func TestTooOldAgent(t *testing.T) {
+	// Old agents close the connection on unknown commands.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Read(make([]byte, 1))
+			conn.Close()
+		}
+	}()
+
+	commandName = "vars"
+	defer func() { commandName = "" }()
+	_, err = request(*l.Addr().(*net.TCPAddr), signal.Vars, nil)
+	if err == nil || err.Error() != "agent too old for vars" {
+		t.Errorf("got error %v; want agent too old for vars", err)
+	}
+	if !errors.Is(err, errUnsupported) {
+		t.Errorf("got error %v; want it to be errUnsupported", err)
+	}
+}
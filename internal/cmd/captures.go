@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func triggeredCaptures(addr net.TCPAddr, params []string) error {
+	out, err := request(addr, signal.ListCaptures, nil)
+	if err != nil {
+		return err
+	}
+	var files []internal.HistoryFile
+	if err := json.Unmarshal(out, &files); err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tREASON")
+		for _, f := range files {
+			if f.Kind != "reason" {
+				continue
+			}
+			reason, err := request(addr, signal.FetchCapture, f.Name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%v\t%s\n", f.Time.Local().Format(time.RFC3339), strings.TrimSpace(string(reason)))
+		}
+		return w.Flush()
+	}
+
+	at, err := parseHistoryTime(params[0], time.Now())
+	if err != nil {
+		return err
+	}
+	kinds := []string{"reason", "memstats"}
+	if len(params) > 1 {
+		kinds = params[1:2]
+	}
+	for _, kind := range kinds {
+		f, ok := findHistoryFile(files, at, kind)
+		if !ok {
+			return fmt.Errorf("no %s captured at or before %v, want one of reason, memstats, goroutines or heap", kind, at.Format(time.RFC3339))
+		}
+		out, err := request(addr, signal.FetchCapture, f.Name)
+		if err != nil {
+			return err
+		}
+		if kind == "heap" {
+			fmt.Printf("Using heap profile captured at %v\n", f.Time.Local().Format(time.RFC3339))
+			return openProfile(addr, out, kind)
+		}
+		fmt.Printf("%s", out)
+	}
+	return nil
+}
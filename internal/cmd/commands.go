@@ -0,0 +1,50 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"text/tabwriter"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func listCommands(addr net.TCPAddr, _ []string) error {
+	out, err := request(addr, signal.ListCommands, nil)
+	if err != nil {
+		return err
+	}
+	var cmds []internal.Command
+	if err := json.Unmarshal(out, &cmds); err != nil {
+		return err
+	}
+	return printCommands(os.Stdout, cmds)
+}
+
+func printCommands(w io.Writer, cmds []internal.Command) error {
+	if len(cmds) == 0 {
+		_, err := fmt.Fprintln(w, "No commands registered.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tDESCRIPTION")
+	for _, c := range cmds {
+		fmt.Fprintf(tw, "%s\t%s\n", c.Name, c.Description)
+	}
+	return tw.Flush()
+}
+
+func callCommand(addr net.TCPAddr, params []string) error {
+	if len(params) == 0 {
+		return errors.New("missing command name")
+	}
+	return requestWithPrint(addr, signal.CallCommand, internal.CommandCall{Name: params[0], Args: params[1:]})
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var (
+	coverageOut  string
+	coverageOpts internal.CoverageOptions
+)
+
+func coverageFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&coverageOut, "out", "o", "covdata", "directory to write the coverage data files to")
+	fs.BoolVar(&coverageOpts.Reset, "reset", false, "clear the counters once written, e.g. between test phases")
+}
+
+func coverage(addr net.TCPAddr, _ []string) error {
+	r, err := requestLazy(addr, signal.Coverage, coverageOpts)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	files, err := extractCoverage(r, coverageOut)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d coverage data files to: %s\n", len(files), coverageOut)
+	fmt.Printf("Run `go tool covdata percent -i %s` to report the coverage.\n", coverageOut)
+	return nil
+}
+
+// extractCoverage writes the files of the tar archive read from r to dir,
+// created if needed, and returns their names.
+func extractCoverage(r io.Reader, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var files []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		name := hdr.Name
+		if filepath.Base(name) != name || name == "." || name == ".." {
+			return files, fmt.Errorf("invalid file name %q in coverage data", name)
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return files, err
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return files, err
+		}
+		files = append(files, name)
+	}
+}
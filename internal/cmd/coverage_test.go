@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func coverageTar(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractCoverage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "covdata")
+	files, err := extractCoverage(coverageTar(t, map[string]string{"covmeta.abc": "meta"}), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "covmeta.abc" {
+		t.Errorf("got files %q", files)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "covmeta.abc")); err != nil || string(b) != "meta" {
+		t.Errorf("got %q, %v", b, err)
+	}
+
+	for _, name := range []string{"../covmeta.abc", "/tmp/covmeta.abc", ".."} {
+		if _, err := extractCoverage(coverageTar(t, map[string]string{name: "meta"}), dir); err == nil {
+			t.Errorf("extracted %q", name)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/spf13/cobra"
+)
+
+// CrashesCommand lists or prints the crash reports of dead processes.
+func CrashesCommand() *cobra.Command {
+	var clean bool
+	cmd := &cobra.Command{
+		Use:   "crashes [pid]",
+		Short: "Lists or prints the crash output of dead processes whose agent captured it.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gopsdir, err := internal.ConfigDir()
+			if err != nil {
+				return err
+			}
+			reports, err := readCrashReports(internal.CrashDir(gopsdir), isAlive)
+			if err != nil {
+				return err
+			}
+			switch {
+			case clean:
+				for _, r := range reports {
+					if err := os.Remove(r.file); err != nil {
+						return err
+					}
+				}
+				return nil
+			case len(args) == 1:
+				pid, err := strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("error parsing the PID: %w", err)
+				}
+				for _, r := range reports {
+					if r.PID == pid {
+						return printCrashReport(os.Stdout, r)
+					}
+				}
+				return fmt.Errorf("no crash report for dead process %d", pid)
+			}
+			return printCrashReports(os.Stdout, reports)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolVar(&clean, "clean", false, "remove the crash reports of dead processes")
+	return cmd
+}
+
+// isAlive reports whether the process with the given PID that started at
+// start is alive, rather than a later process that got the same PID.
+func isAlive(pid int, start time.Time) bool {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return !errors.Is(err, process.ErrorProcessNotRunning)
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return true
+	}
+	// The agent records the start time right after the process is
+	// created, while a later process is created after the earlier one
+	// died. The creation time is only precise to a second or so.
+	return time.UnixMilli(created).Before(start.Add(time.Second))
+}
+
+// crashReport is the crash report of a dead process.
+type crashReport struct {
+	internal.CrashHeader
+	file   string
+	output []byte    // empty if the process didn't crash
+	time   time.Time // when the output was written
+}
+
+// readCrashReports reads the crash reports in dir of the processes that
+// aren't alive, most recent first.
+func readCrashReports(dir string, alive func(pid int, start time.Time) bool) ([]crashReport, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var reports []crashReport
+	for _, e := range entries {
+		// The name is <pid>-<start>, or <pid> for older agents.
+		pid, _, _ := strings.Cut(e.Name(), "-")
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping crash report: %v\n", err)
+			continue
+		}
+		h, output, err := internal.ParseCrashReport(b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping crash report %s: %v\n", file, err)
+			continue
+		}
+		if alive(h.PID, h.Start) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, crashReport{CrashHeader: h, file: file, output: output, time: info.ModTime()})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].time.After(reports[j].time) })
+	return reports, nil
+}
+
+const crashTimeFormat = "2006-01-02 15:04:05"
+
+func printCrashReports(w io.Writer, reports []crashReport) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tSTARTED\tCRASHED\tGO\tPATH\tERROR")
+	for _, r := range reports {
+		crashed, msg := "-", "(no crash output, the process exited or was killed)"
+		if len(r.output) > 0 {
+			crashed, msg = r.time.Format(crashTimeFormat), firstLine(r.output)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			r.PID, r.Start.Local().Format(crashTimeFormat), crashed, r.GoVersion, r.Path, msg)
+	}
+	return tw.Flush()
+}
+
+func printCrashReport(w io.Writer, r crashReport) error {
+	fmt.Fprintf(w, "pid: %d\npath: %s\ngo: %s\nstarted: %v\n", r.PID, r.Path, r.GoVersion, r.Start.Local())
+	if len(r.output) == 0 {
+		_, err := fmt.Fprintln(w, "\nno crash output, the process exited or was killed")
+		return err
+	}
+	fmt.Fprintf(w, "crashed: %v\n\n", r.time)
+	_, err := w.Write(r.output)
+	return err
+}
+
+// firstLine returns the first non-empty line of b, such as
+// "panic: something went wrong".
+func firstLine(b []byte) string {
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return string(line)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestReadCrashReports(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2017, 1, 1, 15, 4, 5, 0, time.UTC)
+	for i, output := range []string{"panic: boom\n\ngoroutine 1 [running]:\n", "", "fatal error: alive\n", "panic: reused\n"} {
+		pid := 100 + i
+		pstart := start
+		if i == 3 {
+			// An earlier process with the PID of the alive one.
+			pid, pstart = 102, start.Add(-time.Hour)
+		}
+		var buf bytes.Buffer
+		h := internal.CrashHeader{PID: pid, Path: "/bin/app", GoVersion: "go1.23.0", Start: pstart}
+		if err := h.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteString(output)
+		file := filepath.Join(dir, internal.CrashFile(pid, pstart))
+		if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := start.Add(time.Duration(i) * time.Hour)
+		if i == 3 {
+			mtime = start.Add(30 * time.Minute)
+		}
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A malformed report is skipped.
+	if err := os.WriteFile(filepath.Join(dir, "104-1"), []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	alive := func(pid int, pstart time.Time) bool { return pid == 102 && pstart.Equal(start) }
+	reports, err := readCrashReports(dir, alive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("got %d reports; want 3", len(reports))
+	}
+	if r := reports[0]; r.PID != 101 || len(r.output) != 0 {
+		t.Errorf("got most recent report of %d with output %q; want 101 without output", r.PID, r.output)
+	}
+	if r := reports[1]; r.PID != 102 || firstLine(r.output) != "panic: reused" {
+		t.Errorf("got report %+v with output %q; want the earlier process 102", r.CrashHeader, r.output)
+	}
+	if r := reports[2]; r.PID != 100 || r.Path != "/bin/app" || r.GoVersion != "go1.23.0" || !r.Start.Equal(start) {
+		t.Errorf("got report %+v", r.CrashHeader)
+	}
+
+	var buf bytes.Buffer
+	if err := printCrashReports(&buf, reports); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(lines[3], "panic: boom") {
+		t.Errorf("got:\n%s", buf.String())
+	}
+}
+
+func TestIsAlive(t *testing.T) {
+	if !isAlive(os.Getpid(), time.Now()) {
+		t.Error("the current process isn't alive")
+	}
+	// A report of an earlier process with the same PID.
+	if isAlive(os.Getpid(), time.Now().Add(-time.Hour)) {
+		t.Error("an earlier process with the PID of the current process is alive")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var dbStatsWatch time.Duration
+
+func dbStatsFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&dbStatsWatch, "watch", 0, "re-query at the given interval and print deltas")
+}
+
+// dbStatFields are the printed fields of internal.DBPoolStats.
+var dbStatFields = []struct {
+	key   string
+	value func(s *internal.DBPoolStats) int64
+	dur   bool
+}{
+	{key: "max-open", value: func(s *internal.DBPoolStats) int64 { return int64(s.MaxOpenConnections) }},
+	{key: "open", value: func(s *internal.DBPoolStats) int64 { return int64(s.OpenConnections) }},
+	{key: "in-use", value: func(s *internal.DBPoolStats) int64 { return int64(s.InUse) }},
+	{key: "idle", value: func(s *internal.DBPoolStats) int64 { return int64(s.Idle) }},
+	{key: "wait-count", value: func(s *internal.DBPoolStats) int64 { return s.WaitCount }},
+	{key: "wait-duration", value: func(s *internal.DBPoolStats) int64 { return int64(s.WaitDuration) }, dur: true},
+	{key: "max-idle-closed", value: func(s *internal.DBPoolStats) int64 { return s.MaxIdleClosed }},
+	{key: "max-idle-time-closed", value: func(s *internal.DBPoolStats) int64 { return s.MaxIdleTimeClosed }},
+	{key: "max-lifetime-closed", value: func(s *internal.DBPoolStats) int64 { return s.MaxLifetimeClosed }},
+}
+
+func dbStats(addr net.TCPAddr, params []string) error {
+	var (
+		prev  map[string]internal.DBPoolStats
+		prevT time.Time
+	)
+	for {
+		out, err := request(addr, signal.DBStats, params)
+		if err != nil {
+			return err
+		}
+		var stats []internal.DBStats
+		if err := json.Unmarshal(out, &stats); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := printDBStats(os.Stdout, stats, prev, now, now.Sub(prevT)); err != nil {
+			return err
+		}
+		if dbStatsWatch <= 0 {
+			return nil
+		}
+		prev, prevT = make(map[string]internal.DBPoolStats, len(stats)), now
+		for _, s := range stats {
+			prev[s.Name] = s.Stats
+		}
+		time.Sleep(dbStatsWatch)
+	}
+}
+
+// printDBStats prints stats, read at now, along with the changes since
+// prev, read d earlier, if not nil. Changed fields are marked with a star.
+func printDBStats(w io.Writer, stats []internal.DBStats, prev map[string]internal.DBPoolStats, now time.Time, d time.Duration) error {
+	if len(stats) == 0 {
+		_, err := fmt.Fprintln(w, "No databases registered.")
+		return err
+	}
+	if prev != nil {
+		fmt.Fprintf(w, "=== %v, over %v\n", now.Format("15:04:05"), d.Round(time.Millisecond))
+	}
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s:\n", s.Name)
+		p, hasPrev := prev[s.Name]
+		for _, f := range dbStatFields {
+			v := f.value(&s.Stats)
+			delta := v - f.value(&p)
+			mark := " "
+			if hasPrev && delta != 0 {
+				mark = "*"
+			}
+			value := fmt.Sprint(v)
+			if f.dur {
+				value = time.Duration(v).String()
+			}
+			fmt.Fprintf(w, "%s %s: %s", mark, f.key, value)
+			if f.key == "in-use" && v > 0 && v == int64(s.Stats.MaxOpenConnections) {
+				fmt.Fprint(w, " (pool exhausted)")
+			}
+			if hasPrev && delta != 0 {
+				if f.dur {
+					fmt.Fprintf(w, " [%s]", signedDuration(time.Duration(delta)))
+				} else {
+					fmt.Fprintf(w, " [%s]", signedNum(float64(delta)))
+				}
+			}
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func signedDuration(d time.Duration) string {
+	if d >= 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPrintDBStats(t *testing.T) {
+	prev := map[string]internal.DBPoolStats{
+		"main": {MaxOpenConnections: 10, OpenConnections: 8, InUse: 6, Idle: 2, WaitCount: 3, WaitDuration: time.Second},
+	}
+	stats := []internal.DBStats{{
+		Name:  "main",
+		Stats: internal.DBPoolStats{MaxOpenConnections: 10, OpenConnections: 10, InUse: 10, WaitCount: 10, WaitDuration: 3500 * time.Millisecond, MaxLifetimeClosed: 2},
+	}}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := printDBStats(&buf, stats, prev, now, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	want := `=== 12:00:00, over 1s
+main:
+  max-open: 10
+* open: 10 [+2]
+* in-use: 10 (pool exhausted) [+4]
+* idle: 0 [-2]
+* wait-count: 10 [+7]
+* wait-duration: 3.5s [+2.5s]
+  max-idle-closed: 0
+  max-idle-time-closed: 0
+* max-lifetime-closed: 2 [+2]
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
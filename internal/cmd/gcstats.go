@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var gcStatsHistory int
+
+func gcStatsFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&gcStatsHistory, "history", 10, "number of recent pauses to print")
+}
+
+func gcStats(addr net.TCPAddr, _ []string) error {
+	out, err := request(addr, signal.GCStats, nil)
+	if err != nil {
+		return err
+	}
+	var s debug.GCStats
+	if err := json.Unmarshal(out, &s); err != nil {
+		return err
+	}
+	printGCStats(os.Stdout, &s, gcStatsHistory)
+	return nil
+}
+
+// printGCStats prints s, whose PauseQuantiles are percentiles, along with
+// the given number of most recent pauses.
+func printGCStats(w io.Writer, s *debug.GCStats, history int) {
+	fmt.Fprintf(w, "num-gc: %v\n", s.NumGC)
+	if s.NumGC == 0 {
+		return
+	}
+	fmt.Fprintf(w, "last-gc: %v\n", s.LastGC)
+	fmt.Fprintf(w, "pause-total: %v\n", s.PauseTotal)
+	if q := s.PauseQuantiles; len(q) == 101 {
+		fmt.Fprintf(w, "pause-p50: %v\n", q[50])
+		fmt.Fprintf(w, "pause-p90: %v\n", q[90])
+		fmt.Fprintf(w, "pause-p99: %v\n", q[99])
+		fmt.Fprintf(w, "pause-max: %v\n", q[100])
+	}
+	// The pause history is the most recent first.
+	if n := len(s.PauseEnd); n > 1 {
+		window := s.PauseEnd[0].Sub(s.PauseEnd[n-1])
+		if window > 0 {
+			fmt.Fprintf(w, "gc-frequency: %.2f/min over the last %d collections (%v)\n",
+				float64(n-1)/window.Minutes(), n, window.Round(time.Second))
+			fmt.Fprintf(w, "gc-interval-mean: %v\n", (window / time.Duration(n-1)).Round(time.Millisecond))
+		}
+	}
+	if history > len(s.Pause) {
+		history = len(s.Pause)
+	}
+	if history > 0 {
+		fmt.Fprintf(w, "recent-pauses:\n")
+		for i := 0; i < history && i < len(s.PauseEnd); i++ {
+			fmt.Fprintf(w, "  %v  %v\n", s.PauseEnd[i].Format("2006-01-02 15:04:05.000"), s.Pause[i])
+		}
+	}
+}
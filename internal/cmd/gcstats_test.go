@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintGCStats(t *testing.T) {
+	end := time.Date(2017, 1, 1, 15, 4, 5, 0, time.UTC)
+	s := &debug.GCStats{
+		NumGC:          3,
+		LastGC:         end,
+		PauseTotal:     6 * time.Millisecond,
+		Pause:          []time.Duration{3 * time.Millisecond, 2 * time.Millisecond, time.Millisecond},
+		PauseEnd:       []time.Time{end, end.Add(-30 * time.Second), end.Add(-time.Minute)},
+		PauseQuantiles: make([]time.Duration, 101),
+	}
+	for i := range s.PauseQuantiles {
+		s.PauseQuantiles[i] = time.Duration(i) * time.Microsecond
+	}
+	var buf bytes.Buffer
+	printGCStats(&buf, s, 2)
+	for _, want := range []string{
+		"pause-p90: 90µs\n",
+		"pause-max: 100µs\n",
+		"gc-frequency: 2.00/min over the last 3 collections (1m0s)\n",
+		"gc-interval-mean: 30s\n",
+		"recent-pauses:\n  2017-01-01 15:04:05.000  3ms\n  2017-01-01 15:03:35.000  2ms\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, buf.String())
+		}
+	}
+}
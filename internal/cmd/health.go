@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func health(addr net.TCPAddr, params []string) error {
+	out, err := request(addr, signal.HealthCheck, params)
+	if err != nil {
+		return err
+	}
+	var results []internal.HealthResult
+	if err := json.Unmarshal(out, &results); err != nil {
+		return err
+	}
+	if err := printHealth(os.Stdout, results); err != nil {
+		return err
+	}
+	if n := failedChecks(results); n > 0 {
+		return fmt.Errorf("%d of %d health checks failed", n, len(results))
+	}
+	return nil
+}
+
+func printHealth(w io.Writer, results []internal.HealthResult) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "No health checks registered.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tLATENCY\tSTATUS")
+	for _, res := range results {
+		status := "ok"
+		if res.Error != "" {
+			status = "FAIL: " + res.Error
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%s\n", res.Name, res.Latency.Round(time.Microsecond), status)
+	}
+	return tw.Flush()
+}
+
+func failedChecks(results []internal.HealthResult) int {
+	var n int
+	for _, res := range results {
+		if res.Error != "" {
+			n++
+		}
+	}
+	return n
+}
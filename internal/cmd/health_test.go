@@ -0,0 +1,34 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPrintHealth(t *testing.T) {
+	results := []internal.HealthResult{
+		{Name: "cache", Latency: 150 * time.Microsecond},
+		{Name: "database", Latency: 2 * time.Second, Error: "timed out after 2s"},
+	}
+	var buf bytes.Buffer
+	if err := printHealth(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	want := `NAME      LATENCY  STATUS
+cache     150µs    ok
+database  2s       FAIL: timed out after 2s
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if n := failedChecks(results); n != 1 {
+		t.Errorf("failedChecks() = %d; want 1", n)
+	}
+}
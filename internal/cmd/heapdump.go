@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var (
+	heapDumpOut string
+	heapDumpYes bool
+)
+
+func heapDumpFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&heapDumpOut, "out", "o", "", "file to write the heap dump to (default heapdump-<time>)")
+	fs.BoolVarP(&heapDumpYes, "yes", "y", false, "don't ask for confirmation")
+}
+
+func heapDump(addr net.TCPAddr, _ []string) error {
+	if !heapDumpYes {
+		out, err := cmd(addr, signal.MemStats)
+		if err != nil {
+			return err
+		}
+		size := "unknown"
+		for _, s := range parseMemStats(out) {
+			if s.key == "heap-sys" {
+				size = s.value
+			}
+		}
+		fmt.Printf("The process is stopped while the heap dump is written, which may take a while.\n")
+		fmt.Printf("The heap dump will be about as large as the heap: %s.\n", size)
+		fmt.Printf("Continue? [y/N] ")
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errors.New("heap dump canceled")
+		}
+	}
+
+	out := heapDumpOut
+	if out == "" {
+		out = "heapdump-" + time.Now().Format("20060102T150405")
+	}
+	r, err := requestLazy(addr, signal.HeapDump, nil)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Heap dump of %s saved to: %s\n", fmtBytes(float64(n)), out)
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var logLevelFor time.Duration
+
+func logLevelFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&logLevelFor, "for", 0, "revert the change after the given duration")
+}
+
+// levelRE matches the levels accepted by slog.Level.UnmarshalText.
+var levelRE = regexp.MustCompile(`^(?i)(debug|info|warn|error)([+-][0-9]+)?$`)
+
+func logLevel(addr net.TCPAddr, params []string) error {
+	var change internal.LogLevelChange
+	switch len(params) {
+	case 0:
+	case 1:
+		if levelRE.MatchString(params[0]) {
+			change.Level = params[0]
+		} else {
+			change.Logger = params[0]
+		}
+	case 2:
+		change.Logger, change.Level = params[0], params[1]
+	default:
+		return errors.New("too many arguments")
+	}
+	if change.Level == "" && logLevelFor != 0 {
+		return errors.New("--for requires a level")
+	}
+	change.For = logLevelFor
+
+	out, err := request(addr, signal.LogLevel, change)
+	if err != nil {
+		return err
+	}
+	var levels []internal.LogLevel
+	if err := json.Unmarshal(out, &levels); err != nil {
+		return err
+	}
+	return printLogLevels(os.Stdout, levels, time.Now())
+}
+
+func printLogLevels(w io.Writer, levels []internal.LogLevel, now time.Time) error {
+	if len(levels) == 0 {
+		_, err := fmt.Fprintln(w, "No loggers registered.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOGGER\tLEVEL")
+	for _, l := range levels {
+		if l.RevertTo != "" && l.RevertAt != nil {
+			fmt.Fprintf(tw, "%s\t%s (reverts to %s in %v)\n", l.Logger, l.Level, l.RevertTo, l.RevertAt.Sub(now).Round(time.Second))
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\n", l.Logger, l.Level)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPrintLogLevels(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	revertAt := now.Add(5 * time.Minute)
+	levels := []internal.LogLevel{
+		{Logger: "db", Level: "INFO"},
+		{Logger: "http", Level: "DEBUG", RevertTo: "INFO", RevertAt: &revertAt},
+	}
+	var buf bytes.Buffer
+	if err := printLogLevels(&buf, levels, now); err != nil {
+		t.Fatal(err)
+	}
+	want := `LOGGER  LEVEL
+db      INFO
+http    DEBUG (reverts to INFO in 5m0s)
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestLevelRE(t *testing.T) {
+	for s, want := range map[string]bool{
+		"debug":   true,
+		"WARN":    true,
+		"info+2":  true,
+		"error-4": true,
+		"http":    false,
+		"debugx":  false,
+	} {
+		if got := levelRE.MatchString(s); got != want {
+			t.Errorf("levelRE.MatchString(%q) = %v; want %v", s, got, want)
+		}
+	}
+}
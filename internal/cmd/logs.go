@@ -0,0 +1,24 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"net"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var logsOpts internal.LogsOptions
+
+func logsFlags(fs *pflag.FlagSet) {
+	fs.IntVarP(&logsOpts.Lines, "lines", "n", 0, "print the given number of last lines (default all the kept lines)")
+	fs.BoolVarP(&logsOpts.Follow, "follow", "f", false, "keep printing new lines until interrupted")
+}
+
+func logs(addr net.TCPAddr, _ []string) error {
+	return requestWithPrint(addr, signal.Logs, logsOpts)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var memStatsWatch time.Duration
+
+func memStatsFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&memStatsWatch, "watch", 0, "re-query at the given interval and print deltas and rates")
+}
+
+// memStat is a field of the memory stats printed by the agent.
+type memStat struct {
+	key   string
+	value string
+	num   float64 // numeric value, in bytes for sizes
+	isNum bool
+	bytes bool
+}
+
+// parseMemStats parses the "key: value" lines of the memory stats.
+func parseMemStats(out []byte) []memStat {
+	var stats []memStat
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		s := memStat{key: key, value: value}
+		num := value
+		if i := strings.Index(num, "("); i >= 0 {
+			// e.g. "1.00KB (1024 bytes)"
+			num = num[i+1:]
+		}
+		if n := strings.TrimSuffix(strings.TrimSuffix(num, ")"), " bytes"); n != num {
+			num, s.bytes = n, true
+		}
+		if f, err := strconv.ParseFloat(num, 64); err == nil {
+			s.num, s.isNum = f, true
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// printMemStatsDelta prints stats, read at now, along with the changes since
+// prev, read d earlier. Changed fields are marked with a star.
+func printMemStatsDelta(w io.Writer, stats, prev []memStat, now time.Time, d time.Duration) {
+	old := make(map[string]memStat, len(prev))
+	for _, s := range prev {
+		old[s.key] = s
+	}
+	delta := func(key string) float64 {
+		for _, s := range stats {
+			if s.key == key {
+				return s.num - old[key].num
+			}
+		}
+		return 0
+	}
+	if len(prev) > 0 {
+		secs := d.Seconds()
+		fmt.Fprintf(w, "=== %v, over %v: alloc %s/s, mallocs %.0f/s, gc %.2f/s, heap %s\n",
+			now.Format("15:04:05"), d.Round(time.Millisecond),
+			fmtBytes(delta("total-alloc")/secs), delta("mallocs")/secs, delta("num-gc")/secs,
+			signedBytes(delta("heap-alloc")))
+	}
+	for _, s := range stats {
+		p, ok := old[s.key]
+		mark := " "
+		if ok && p.value != s.value {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s %s: %s", mark, s.key, s.value)
+		if ok && s.isNum && p.isNum && s.num != p.num {
+			if s.bytes {
+				fmt.Fprintf(w, " [%s]", signedBytes(s.num-p.num))
+			} else {
+				fmt.Fprintf(w, " [%s]", signedNum(s.num-p.num))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func fmtBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for ; (b >= 1024 || b <= -1024) && i < len(units)-1; i++ {
+		b /= 1024
+	}
+	return fmt.Sprintf("%.2f%s", b, units[i])
+}
+
+func signedNum(n float64) string {
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	if n >= 0 {
+		return "+" + s
+	}
+	return s
+}
+
+func signedBytes(b float64) string {
+	if b >= 0 {
+		return "+" + fmtBytes(b)
+	}
+	return fmtBytes(b)
+}
+
+func watchMemStats(addr net.TCPAddr, interval time.Duration) error {
+	var (
+		prev  []memStat
+		prevT time.Time
+	)
+	for {
+		out, err := cmd(addr, signal.MemStats)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		stats := parseMemStats(out)
+		printMemStatsDelta(os.Stdout, stats, prev, now, now.Sub(prevT))
+		prev, prevT = stats, now
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintMemStatsDelta(t *testing.T) {
+	prev := parseMemStats([]byte(`total-alloc: 1.00MB (1048576 bytes)
+mallocs: 100
+heap-alloc: 512 bytes
+num-gc: 1
+last-gc: 2017-01-01 15:04:05 +0000 UTC
+`))
+	stats := parseMemStats([]byte(`total-alloc: 3.00MB (3145728 bytes)
+mallocs: 300
+heap-alloc: 1.00KB (1024 bytes)
+num-gc: 1
+last-gc: 2017-01-01 15:04:06 +0000 UTC
+`))
+	var buf bytes.Buffer
+	now := time.Date(2017, 1, 1, 15, 4, 7, 0, time.UTC)
+	printMemStatsDelta(&buf, stats, prev, now, 2*time.Second)
+	want := `=== 15:04:07, over 2s: alloc 1.00MB/s, mallocs 100/s, gc 0.00/s, heap +512.00B
+* total-alloc: 3.00MB (3145728 bytes) [+2.00MB]
+* mallocs: 300 [+200]
+* heap-alloc: 1.00KB (1024 bytes) [+512.00B]
+  num-gc: 1
+* last-gc: 2017-01-01 15:04:06 +0000 UTC
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	printMemStatsDelta(&buf, prev, nil, now, 0)
+	if got := buf.String(); strings.Contains(got, "===") || strings.Contains(got, "*") {
+		t.Errorf("got deltas without previous stats:\n%s", got)
+	}
+}
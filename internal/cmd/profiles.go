@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func profilesHistory(addr net.TCPAddr, params []string) error {
+	out, err := request(addr, signal.ListProfiles, nil)
+	if err != nil {
+		return err
+	}
+	var files []internal.HistoryFile
+	if err := json.Unmarshal(out, &files); err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		printHistory(files)
+		return nil
+	}
+
+	at, err := parseHistoryTime(params[0], time.Now())
+	if err != nil {
+		return err
+	}
+	kind := "cpu"
+	if len(params) > 1 {
+		kind = params[1]
+	}
+	f, ok := findHistoryFile(files, at, kind)
+	if !ok {
+		return fmt.Errorf("no %s profile captured at or before %v", kind, at.Format(time.RFC3339))
+	}
+	fmt.Printf("Using %s profile captured at %v\n", f.Kind, f.Time.Local().Format(time.RFC3339))
+	out, err = request(addr, signal.FetchProfile, f.Name)
+	if err != nil {
+		return err
+	}
+	return openProfile(addr, out, f.Kind)
+}
+
+func printHistory(files []internal.HistoryFile) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tKIND\tSIZE")
+	for _, f := range files {
+		fmt.Fprintf(w, "%v\t%s\t%d\n", f.Time.Local().Format(time.RFC3339), f.Kind, f.Size)
+	}
+	w.Flush()
+}
+
+// parseHistoryTime parses either a time in RFC 3339 format or an age, such
+// as "1h", relative to now.
+func parseHistoryTime(s string, now time.Time) (time.Time, error) {
+	if age, err := time.ParseDuration(s); err == nil {
+		return now.Add(-age), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time or age %q, want e.g. 2006-01-02T15:04:05Z or 1h", s)
+	}
+	return t, nil
+}
+
+// findHistoryFile returns the most recent file of the given kind captured at
+// or before t. files are sorted oldest first.
+func findHistoryFile(files []internal.HistoryFile, t time.Time, kind string) (internal.HistoryFile, bool) {
+	for i := len(files) - 1; i >= 0; i-- {
+		if f := files[i]; f.Kind == kind && !f.Time.After(t) {
+			return f, true
+		}
+	}
+	return internal.HistoryFile{}, false
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestFindHistoryFile(t *testing.T) {
+	now := time.Date(2017, 1, 1, 16, 0, 0, 0, time.UTC)
+	files := []internal.HistoryFile{
+		{Name: "a", Time: now.Add(-2 * time.Hour), Kind: "cpu"},
+		{Name: "b", Time: now.Add(-2 * time.Hour), Kind: "heap"},
+		{Name: "c", Time: now.Add(-time.Hour), Kind: "cpu"},
+		{Name: "d", Time: now.Add(-time.Hour), Kind: "heap"},
+	}
+	tests := []struct {
+		at   string
+		kind string
+		want string
+	}{
+		{"0s", "cpu", "c"},
+		{"90m", "heap", "b"},
+		{"1h", "heap", "d"},
+		{"2017-01-01T14:30:00Z", "cpu", "a"},
+		{"3h", "cpu", ""},
+	}
+	for _, tt := range tests {
+		at, err := parseHistoryTime(tt.at, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, _ := findHistoryFile(files, at, tt.kind)
+		if f.Name != tt.want {
+			t.Errorf("findHistoryFile(%v, %v) = %q; want %q", tt.at, tt.kind, f.Name, tt.want)
+		}
+	}
+	if _, err := parseHistoryTime("yesterday", now); err == nil {
+		t.Error("parseHistoryTime(yesterday) succeeded; want error")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/gops/goroutine"
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var (
+	requestsStack  bool
+	requestsMinAge time.Duration
+)
+
+func requestsFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&requestsStack, "stack", false, "print the stack of the goroutine serving each request")
+	fs.DurationVar(&requestsMinAge, "min-age", 0, "only print the requests served for at least the given duration")
+}
+
+func requests(addr net.TCPAddr, _ []string) error {
+	out, err := request(addr, signal.Requests, nil)
+	if err != nil {
+		return err
+	}
+	var reqs []internal.Request
+	if err := json.Unmarshal(out, &reqs); err != nil {
+		return err
+	}
+	var n int
+	for _, req := range reqs {
+		if req.Age >= requestsMinAge {
+			reqs[n] = req
+			n++
+		}
+	}
+	reqs = reqs[:n]
+	if !requestsStack || len(reqs) == 0 {
+		return printRequests(os.Stdout, reqs)
+	}
+
+	opts := internal.StackOptions{}
+	for _, req := range reqs {
+		opts.IDs = append(opts.IDs, req.Goroutine)
+	}
+	out, err = request(addr, signal.FilteredStackTrace, opts)
+	if err != nil {
+		return err
+	}
+	return printRequestStacks(os.Stdout, reqs, stacksByID(string(out)))
+}
+
+func printRequests(w io.Writer, reqs []internal.Request) error {
+	if len(reqs) == 0 {
+		_, err := fmt.Fprintln(w, "No requests in flight.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "AGE\tGOROUTINE\tREMOTE\tMETHOD\tPATH")
+	for _, req := range reqs {
+		fmt.Fprintf(tw, "%v\t%d\t%s\t%s\t%s\n", req.Age.Round(time.Millisecond), req.Goroutine, req.Remote, req.Method, req.Path)
+	}
+	return tw.Flush()
+}
+
+// printRequestStacks prints each request followed by the stack of the
+// goroutine serving it, if still running.
+func printRequestStacks(w io.Writer, reqs []internal.Request, stacks map[int64]string) error {
+	for _, req := range reqs {
+		fmt.Fprintf(w, "%s %s from %s, for %v:\n", req.Method, req.Path, req.Remote, req.Age.Round(time.Millisecond))
+		stack, ok := stacks[req.Goroutine]
+		if !ok {
+			stack = fmt.Sprintf("goroutine %d is gone", req.Goroutine)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n\n", stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stacksByID splits a goroutine dump into the stacks of each goroutine.
+func stacksByID(dump string) map[int64]string {
+	stacks := make(map[int64]string)
+	for _, block := range strings.Split(dump, "\n\n") {
+		block = strings.TrimSpace(block)
+		header, _, _ := strings.Cut(block, "\n")
+		if g := goroutine.ParseHeader(header); g != nil {
+			stacks[g.ID] = block
+		}
+	}
+	return stacks
+}
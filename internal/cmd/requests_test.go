@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPrintRequestStacks(t *testing.T) {
+	dump := `goroutine 7 [select, 5 minutes]:
+main.export()
+	/src/export.go:20 +0x1d
+
+goroutine 9 [IO wait]:
+main.upload()
+	/src/upload.go:8 +0x2a
+`
+	stacks := stacksByID(dump)
+	if len(stacks) != 2 {
+		t.Fatalf("got %d stacks; want 2", len(stacks))
+	}
+
+	reqs := []internal.Request{
+		{Method: "GET", Path: "/export", Remote: "10.0.0.1:5123", Age: 5*time.Minute + 2*time.Second, Goroutine: 7},
+		{Method: "POST", Path: "/upload", Remote: "10.0.0.2:6001", Age: 1500 * time.Millisecond, Goroutine: 8},
+	}
+	var buf bytes.Buffer
+	if err := printRequests(&buf, reqs); err != nil {
+		t.Fatal(err)
+	}
+	want := `AGE   GOROUTINE  REMOTE         METHOD  PATH
+5m2s  7          10.0.0.1:5123  GET     /export
+1.5s  8          10.0.0.2:6001  POST    /upload
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := printRequestStacks(&buf, reqs, stacks); err != nil {
+		t.Fatal(err)
+	}
+	want = `GET /export from 10.0.0.1:5123, for 5m2s:
+goroutine 7 [select, 5 minutes]:
+main.export()
+	/src/export.go:20 +0x1d
+
+POST /upload from 10.0.0.2:6001, for 1.5s:
+goroutine 8 is gone
+
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -5,7 +5,9 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -14,10 +16,14 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/gops/goroutine"
 	"github.com/google/gops/internal"
+	"github.com/google/gops/internal/profile"
 	"github.com/google/gops/signal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // AgentCommands is a bridge between the legacy multiplexing to commands, and
@@ -34,6 +40,7 @@ func AgentCommands() []*cobra.Command {
 			name:  "stack",
 			short: "Prints the stack trace.",
 			fn:    stackTrace,
+			flags: stackFlags,
 		},
 		{
 			name:  "gc",
@@ -49,6 +56,99 @@ func AgentCommands() []*cobra.Command {
 			name:  "memstats",
 			short: "Prints the allocation and garbage collection stats.",
 			fn:    memStats,
+			flags: memStatsFlags,
+		},
+		{
+			name:  "gctrace",
+			short: "Streams a line per garbage collection until interrupted.",
+			fn:    gcTrace,
+		},
+		{
+			name:  "fds",
+			short: "Lists the open file descriptors and sockets (Linux only).",
+			fn:    fileDescriptors,
+		},
+		{
+			name:  "gcstats",
+			short: "Prints the garbage collection pause distribution and frequency.",
+			fn:    gcStats,
+			flags: gcStatsFlags,
+		},
+		{
+			name:  "heapdump",
+			short: "Writes a full heap dump, as written by debug.WriteHeapDump, to a file.",
+			fn:    heapDump,
+			flags: heapDumpFlags,
+		},
+		{
+			name:  "vars",
+			args:  "[name...]",
+			short: "Prints the variables published with expvar.",
+			fn:    vars,
+			flags: varsFlags,
+		},
+		{
+			name:  "commands",
+			short: "Lists the commands registered by the application.",
+			fn:    listCommands,
+		},
+		{
+			name:     "call",
+			args:     "<name> [args...]",
+			short:    "Runs a command registered by the application.",
+			fn:       callCommand,
+			passArgs: true,
+		},
+		{
+			name:  "get",
+			args:  "[name...]",
+			short: "Prints the variables registered by the application.",
+			fn:    getVars,
+		},
+		{
+			name:     "set",
+			args:     "<name> <value>",
+			short:    "Changes a variable registered by the application.",
+			fn:       setVar,
+			passArgs: true,
+		},
+		{
+			name:  "loglevel",
+			args:  "[logger] [debug|info|warn|error]",
+			short: "Prints or changes the log levels registered by the application.",
+			fn:    logLevel,
+			flags: logLevelFlags,
+		},
+		{
+			name:  "logs",
+			short: "Prints the last log lines kept by the agent.",
+			fn:    logs,
+			flags: logsFlags,
+		},
+		{
+			name:  "requests",
+			short: "Lists the HTTP requests being served by the handlers wrapped by the agent, oldest first.",
+			fn:    requests,
+			flags: requestsFlags,
+		},
+		{
+			name:  "dbstats",
+			args:  "[name...]",
+			short: "Prints the connection pool stats of the databases registered by the application.",
+			fn:    dbStats,
+			flags: dbStatsFlags,
+		},
+		{
+			name:  "coverage",
+			short: "Writes the coverage data of a program built with -cover -covermode=atomic, for \"go tool covdata\".",
+			fn:    coverage,
+			flags: coverageFlags,
+		},
+		{
+			name:  "health",
+			args:  "[name...]",
+			short: "Runs the health checks registered by the application and exits non-zero if any fails.",
+			fn:    health,
 		},
 		{
 			name:  "stats",
@@ -59,28 +159,50 @@ func AgentCommands() []*cobra.Command {
 			name:  "trace",
 			short: "Runs the runtime tracer for 5 secs and launches \"go tool trace\".",
 			fn:    trace,
+			flags: traceFlags,
 		},
 		{
 			name:  "pprof-heap",
 			short: "Reads the heap profile and launches \"go tool pprof\".",
 			fn:    pprofHeap,
+			flags: profileFlags,
 		},
 		{
 			name:  "pprof-cpu",
 			short: "Reads the CPU profile and launches \"go tool pprof\".",
 			fn:    pprofCPU,
+			flags: profileFlags,
+		},
+		{
+			name:  "capabilities",
+			short: "Prints the version of the agent and the commands and options it supports.",
+			fn:    agentCapabilitiesCmd,
 		},
 		{
 			name:  "version",
 			short: "Prints the Go version used to build the program.",
 			fn:    version,
 		},
+		{
+			name:  "profiles-history",
+			args:  "[time|age] [cpu|heap]",
+			short: "Lists the profiles captured in the background or launches \"go tool pprof\" with one of them.",
+			fn:    profilesHistory,
+			flags: profileFlags,
+		},
+		{
+			name:  "captures",
+			args:  "[time|age] [reason|memstats|goroutines|heap]",
+			short: "Lists or prints the diagnostics captured when crossing thresholds.",
+			fn:    triggeredCaptures,
+			flags: profileFlags,
+		},
 	}
 
 	for _, c := range cmds {
 		c := c
-		res = append(res, &cobra.Command{
-			Use:   fmt.Sprintf("%s <pid|addr>", c.name),
+		cc := &cobra.Command{
+			Use:   strings.TrimSpace(fmt.Sprintf("%s <pid|addr> %s", c.name, c.args)),
 			Short: c.short,
 
 			RunE: func(cmd *cobra.Command, args []string) error {
@@ -100,6 +222,7 @@ func AgentCommands() []*cobra.Command {
 					params = append(params, args[1:]...)
 				}
 
+				commandName = c.name
 				if err := c.fn(*addr, params); err != nil {
 					return err
 				}
@@ -110,7 +233,12 @@ func AgentCommands() []*cobra.Command {
 			// errors get double printed otherwise
 			SilenceUsage:  true,
 			SilenceErrors: true,
-		})
+		}
+		if c.flags != nil {
+			c.flags(cc.Flags())
+		}
+		cc.Flags().SetInterspersed(!c.passArgs)
+		res = append(res, cc)
 	}
 
 	return res
@@ -118,8 +246,16 @@ func AgentCommands() []*cobra.Command {
 
 type legacyCommand struct {
 	name  string
+	args  string // arguments following <pid|addr>, if any
 	short string
 	fn    func(addr net.TCPAddr, params []string) error
+
+	// flags optionally registers the flags of the command.
+	flags func(fs *pflag.FlagSet)
+
+	// passArgs stops the parsing of flags at the first argument, for the
+	// following ones to be passed on even if they start with a dash.
+	passArgs bool
 }
 
 func setGC(addr net.TCPAddr, params []string) error {
@@ -143,8 +279,99 @@ func setGC(addr net.TCPAddr, params []string) error {
 	return cmdWithPrint(addr, signal.SetGCPercent, buf...)
 }
 
+var (
+	stackOpts   internal.StackOptions
+	stackJSON   bool
+	stackSample time.Duration
+	stackEvery  time.Duration
+)
+
+func stackFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&stackOpts.State, "state", "", "only print goroutines in the given state, e.g. \"chan receive\"")
+	fs.StringVar(&stackOpts.Func, "func", "", "only print goroutines with a frame whose function matches the regexp")
+	fs.DurationVar(&stackOpts.MinWait, "min-wait", 0, "only print goroutines blocked for at least the given duration")
+	fs.Int64SliceVar(&stackOpts.IDs, "id", nil, "only print the goroutines with the given IDs")
+	fs.IntVar(&stackOpts.Limit, "limit", 0, "print at most the given number of goroutines")
+	fs.StringArrayVar(&stackOpts.Labels, "label", nil, "only print goroutines with the given pprof label, as key=value or key for any value")
+	fs.StringVar(&stackOpts.GroupBy, "group-by", "", "print the number of goroutines by state for each value of the given pprof label")
+	fs.BoolVar(&stackJSON, "json", false, "print one JSON object per goroutine")
+	fs.DurationVar(&stackSample, "sample", 0, "sample the stacks for the given duration and print the most frequent ones, with --limit bounding the number of stacks")
+	fs.DurationVar(&stackEvery, "every", 100*time.Millisecond, "time between two samples with --sample")
+}
+
 func stackTrace(addr net.TCPAddr, _ []string) error {
-	return cmdWithPrint(addr, signal.StackTrace)
+	if stackJSON && stackOpts.GroupBy != "" {
+		return errors.New("--json can't be used along with --group-by")
+	}
+	if stackSample > 0 {
+		return sampleStacks(addr)
+	}
+	var (
+		out []byte
+		err error
+	)
+	if stackOpts.Filtered() {
+		// Otherwise, the goroutines selected by label may be aggregated by
+		// stack, which can't be parsed.
+		stackOpts.PerGoroutine = stackJSON
+		out, err = request(addr, signal.FilteredStackTrace, stackOpts)
+	} else {
+		out, err = cmd(addr, signal.StackTrace)
+	}
+	if err != nil {
+		return err
+	}
+	if !stackJSON {
+		fmt.Printf("%s", out)
+		return nil
+	}
+	gs, err := goroutine.Parse(bytes.NewReader(out))
+	if err != nil {
+		return err
+	}
+	if len(gs) == 0 && len(bytes.TrimSpace(out)) > 0 {
+		return errors.New("failed to parse the goroutines of the dump")
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, g := range gs {
+		if err := enc.Encode(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sampleStacks(addr net.TCPAddr) error {
+	switch {
+	case stackJSON:
+		return errors.New("--json can't be used along with --sample")
+	case stackOpts.GroupBy != "":
+		return errors.New("--group-by can't be used along with --sample")
+	}
+	fmt.Printf("Sampling stacks now, will take %v...\n", stackSample)
+	out, err := request(addr, signal.SampleStacks, internal.SampleOptions{
+		StackOptions: stackOpts,
+		Duration:     stackSample,
+		Interval:     stackEvery,
+	})
+	if err != nil {
+		return err
+	}
+	var s internal.StackSample
+	if err := json.Unmarshal(out, &s); err != nil {
+		return err
+	}
+	fmt.Print(s.Text)
+	f, err := os.CreateTemp("", "stacks_profile")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(s.Profile); err != nil {
+		return err
+	}
+	fmt.Printf("\nProfile saved to: %s\n", f.Name())
+	return nil
 }
 
 func gc(addr net.TCPAddr, _ []string) error {
@@ -152,7 +379,18 @@ func gc(addr net.TCPAddr, _ []string) error {
 	return err
 }
 
+func fileDescriptors(addr net.TCPAddr, _ []string) error {
+	return requestWithPrint(addr, signal.FileDescriptors, nil)
+}
+
+func gcTrace(addr net.TCPAddr, _ []string) error {
+	return requestWithPrint(addr, signal.GCTrace, nil)
+}
+
 func memStats(addr net.TCPAddr, _ []string) error {
+	if memStatsWatch > 0 {
+		return watchMemStats(addr, memStatsWatch)
+	}
 	return cmdWithPrint(addr, signal.MemStats)
 }
 
@@ -169,9 +407,23 @@ func pprofCPU(addr net.TCPAddr, _ []string) error {
 	return pprof(addr, signal.CPUProfile, "cpu")
 }
 
+var traceSnapshot bool
+
+func traceFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&traceSnapshot, "snapshot", false, "dump the recent trace kept by the agent's flight recorder instead")
+}
+
 func trace(addr net.TCPAddr, _ []string) error {
-	fmt.Println("Tracing now, will take 5 secs...")
-	out, err := cmd(addr, signal.Trace)
+	var (
+		out []byte
+		err error
+	)
+	if traceSnapshot {
+		out, err = request(addr, signal.TraceSnapshot, nil)
+	} else {
+		fmt.Println("Tracing now, will take 5 secs...")
+		out, err = cmd(addr, signal.Trace)
+	}
 	if err != nil {
 		return err
 	}
@@ -199,19 +451,43 @@ func trace(addr net.TCPAddr, _ []string) error {
 	return cmd.Run()
 }
 
+var profileBinary bool
+
+func profileFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&profileBinary, "binary", false, "download the binary of the process, for \"go tool pprof\" to disassemble it")
+}
+
 func pprof(addr net.TCPAddr, p byte, prefix string) error {
+	out, err := cmd(addr, p)
+	if err != nil {
+		return err
+	}
+	return openProfile(addr, out, prefix)
+}
+
+// openProfile saves the profile out and launches "go tool pprof" with it.
+// The profile is symbolized by the agent at addr, so that the binary of
+// the process is only downloaded if the agent is too old to do so, or if
+// requested for disassembly.
+func openProfile(addr net.TCPAddr, out []byte, prefix string) error {
+	if len(out) == 0 {
+		return errors.New("failed to read the profile")
+	}
+	symbolized, err := profile.Symbolize(out, func(pcs []uint64) ([][]internal.Frame, error) {
+		return symbolize(addr, pcs)
+	})
+	needBinary := errors.Is(err, errUnsupported)
+	if err != nil && !needBinary {
+		return fmt.Errorf("failed to symbolize the profile: %v", err)
+	}
+	if !needBinary {
+		out = symbolized
+	}
 	tmpDumpFile, err := os.CreateTemp("", prefix+"_profile")
 	if err != nil {
 		return err
 	}
 	{
-		out, err := cmd(addr, p)
-		if err != nil {
-			return err
-		}
-		if len(out) == 0 {
-			return errors.New("failed to read the profile")
-		}
 		if err := os.WriteFile(tmpDumpFile.Name(), out, 0); err != nil {
 			return err
 		}
@@ -222,26 +498,17 @@ func pprof(addr net.TCPAddr, p byte, prefix string) error {
 		}
 		defer os.Remove(tmpDumpFile.Name())
 	}
-	// Download running binary
-	tmpBinFile, err := os.CreateTemp("", "binary")
-	if err != nil {
-		return err
-	}
-	{
-		out, err := cmd(addr, signal.BinaryDump)
+	args := []string{"tool", "pprof"}
+	if needBinary || profileBinary {
+		bin, err := downloadBinary(addr)
 		if err != nil {
-			return fmt.Errorf("failed to read the binary: %v", err)
-		}
-		if len(out) == 0 {
-			return errors.New("failed to read the binary")
-		}
-		defer os.Remove(tmpBinFile.Name())
-		if err := os.WriteFile(tmpBinFile.Name(), out, 0); err != nil {
 			return err
 		}
+		defer os.Remove(bin)
+		fmt.Printf("Binary file saved to: %s\n", bin)
+		args = append(args, bin)
 	}
-	fmt.Printf("Binary file saved to: %s\n", tmpBinFile.Name())
-	cmd := exec.Command("go", "tool", "pprof", tmpBinFile.Name(), tmpDumpFile.Name())
+	cmd := exec.Command("go", append(args, tmpDumpFile.Name())...)
 	cmd.Env = os.Environ()
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -249,6 +516,42 @@ func pprof(addr net.TCPAddr, p byte, prefix string) error {
 	return cmd.Run()
 }
 
+// downloadBinary saves the binary of the process at addr to a temporary
+// file and returns its name.
+func downloadBinary(addr net.TCPAddr) (string, error) {
+	out, err := cmd(addr, signal.BinaryDump)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the binary: %v", err)
+	}
+	if len(out) == 0 {
+		return "", errors.New("failed to read the binary")
+	}
+	tmpBinFile, err := os.CreateTemp("", "binary")
+	if err != nil {
+		return "", err
+	}
+	tmpBinFile.Close()
+	if err := os.WriteFile(tmpBinFile.Name(), out, 0); err != nil {
+		os.Remove(tmpBinFile.Name())
+		return "", err
+	}
+	return tmpBinFile.Name(), nil
+}
+
+// symbolize returns the frames of the program counters pcs of the process
+// at addr.
+func symbolize(addr net.TCPAddr, pcs []uint64) ([][]internal.Frame, error) {
+	out, err := request(addr, signal.Symbolize, pcs)
+	if err != nil {
+		return nil, err
+	}
+	var frames [][]internal.Frame
+	if err := json.Unmarshal(out, &frames); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
 func stats(addr net.TCPAddr, _ []string) error {
 	return cmdWithPrint(addr, signal.Stats)
 }
@@ -300,6 +603,129 @@ func cmd(addr net.TCPAddr, c byte, params ...byte) ([]byte, error) {
 	return all, nil
 }
 
+// request sends a command that reports its status to the agent at addr,
+// with params JSON encoded unless nil, and returns its output.
+func request(addr net.TCPAddr, c byte, params interface{}) ([]byte, error) {
+	r, err := requestLazy(addr, c, params)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func requestWithPrint(addr net.TCPAddr, c byte, params interface{}) error {
+	r, err := requestLazy(addr, c, params)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(os.Stdout, r)
+	return err
+}
+
+// errUnsupported is returned for commands that the agent doesn't know.
+var errUnsupported = errors.New("the agent doesn't support this command, it may be too old")
+
+// commandName is the name of the command being run, for errors.
+var commandName string
+
+// tooOldError is returned for commands that the agent doesn't support.
+type tooOldError struct {
+	name string
+}
+
+func (e *tooOldError) Error() string {
+	return "agent too old for " + e.name
+}
+
+func (e *tooOldError) Is(target error) bool {
+	return target == errUnsupported
+}
+
+// agentCapabilities caches the capabilities of the agents by address, nil
+// for agents too old to report them.
+var agentCapabilities = make(map[string]*internal.Capabilities)
+
+// capabilities returns the capabilities of the agent at addr, or nil if
+// it is too old to report them.
+func capabilities(addr net.TCPAddr) (*internal.Capabilities, error) {
+	if caps, ok := agentCapabilities[addr.String()]; ok {
+		return caps, nil
+	}
+	var caps *internal.Capabilities
+	r, err := dial(addr, signal.Capabilities, nil)
+	switch {
+	case errors.Is(err, errUnsupported):
+	case err != nil:
+		return nil, err
+	default:
+		defer r.Close()
+		caps = new(internal.Capabilities)
+		if err := json.NewDecoder(r).Decode(caps); err != nil {
+			return nil, err
+		}
+	}
+	agentCapabilities[addr.String()] = caps
+	return caps, nil
+}
+
+// requestLazy is like request but returns the connection to the agent,
+// positioned at the start of the output. It fails without sending the
+// command if the agent doesn't support it.
+func requestLazy(addr net.TCPAddr, c byte, params interface{}) (io.ReadCloser, error) {
+	caps, err := capabilities(addr)
+	if err != nil {
+		return nil, err
+	}
+	if caps == nil || !caps.Supports(c) {
+		name := commandName
+		if name == "" {
+			name = fmt.Sprintf("command %#x", c)
+		}
+		return nil, &tooOldError{name: name}
+	}
+	return dial(addr, c, params)
+}
+
+// dial sends the command c, with params JSON encoded unless nil, to the
+// agent at addr and returns the connection, positioned at the start of the
+// output.
+func dial(addr net.TCPAddr, c byte, params interface{}) (io.ReadCloser, error) {
+	var buf []byte
+	if params != nil {
+		var err error
+		if buf, err = json.Marshal(params); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := net.DialTCP("tcp", nil, &addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append([]byte{c}, buf...)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	status := make([]byte, 1)
+	if _, err := io.ReadFull(conn, status); err != nil {
+		conn.Close()
+		if err == io.EOF {
+			return nil, errUnsupported
+		}
+		return nil, err
+	}
+	if status[0] == internal.StatusError {
+		defer conn.Close()
+		msg, err := io.ReadAll(conn)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("agent: %s", msg)
+	}
+	return conn, nil
+}
+
 func cmdLazy(addr net.TCPAddr, c byte, params ...byte) (io.Reader, error) {
 	conn, err := net.DialTCP("tcp", nil, &addr)
 	if err != nil {
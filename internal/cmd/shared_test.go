@@ -27,8 +27,8 @@ func TestCommandPresence(t *testing.T) {
 	// it doesn't test they are correctly _implemented_, just that they are not
 	// missing.
 	wants := []string{
-		"completion", "gc", "memstats", "pprof-cpu", "pprof-heap", "setgc",
-		"stack", "stats", "trace", "version",
+		"call", "capabilities", "commands", "completion", "coverage", "dbstats", "fds", "gc", "gcstats", "gctrace", "get", "health", "heapdump", "loglevel", "logs", "memstats", "pprof-cpu", "pprof-heap", "requests", "set", "setgc",
+		"stack", "stats", "trace", "vars", "version", "profiles-history", "captures",
 	}
 	outs := out.String()
 	for _, want := range wants {
@@ -37,3 +37,26 @@ func TestCommandPresence(t *testing.T) {
 		}
 	}
 }
+
+func TestPassArgs(t *testing.T) {
+	pass := map[string][]string{
+		"call": {"1234", "echo", "-n", "--verbose"},
+		"set":  {"1234", "offset", "-5"},
+	}
+	for _, c := range AgentCommands() {
+		args, ok := pass[c.Name()]
+		if !ok {
+			continue
+		}
+		delete(pass, c.Name())
+		if err := c.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Flags().Args(); strings.Join(got, " ") != strings.Join(args, " ") {
+			t.Errorf("%s: got args %q; want %q", c.Name(), got, args)
+		}
+	}
+	for name := range pass {
+		t.Errorf("%s command not found", name)
+	}
+}
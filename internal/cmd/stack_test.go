@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/agent"
+	"github.com/google/gops/internal"
+)
+
+func TestStackJSONWithoutTracebackLabels(t *testing.T) {
+	// The agent falls back to a debug=1 dump to filter by label.
+	t.Setenv("GODEBUG", "tracebacklabels=0")
+	dir := t.TempDir()
+	if err := agent.Listen(agent.Options{Addr: "127.0.0.1:0", ConfigDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	addr := agentAddr(t, dir)
+
+	done := make(chan struct{})
+	defer close(done)
+	runtimepprof.Do(context.Background(), runtimepprof.Labels("gops_test", "labeled"), func(context.Context) {
+		go func() { <-done }()
+	})
+
+	defer func() {
+		stackOpts = internal.StackOptions{}
+		stackJSON = false
+	}()
+	stackOpts = internal.StackOptions{Labels: []string{"gops_test=labeled"}}
+	stackJSON = true
+	err := stackTrace(addr, nil)
+	if err == nil || !strings.Contains(err.Error(), "tracebacklabels") {
+		t.Errorf("got error %v; want one about traceback labels", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"text/tabwriter"
+
+	"github.com/google/gops/internal"
+	"github.com/google/gops/signal"
+)
+
+func getVars(addr net.TCPAddr, params []string) error {
+	out, err := request(addr, signal.GetVars, params)
+	if err != nil {
+		return err
+	}
+	var vs []internal.Var
+	if err := json.Unmarshal(out, &vs); err != nil {
+		return err
+	}
+	if len(params) == 1 && len(vs) == 1 {
+		// Only the value, for scripts.
+		_, err := fmt.Println(vs[0].Value)
+		return err
+	}
+	return printVars(os.Stdout, vs)
+}
+
+func printVars(w io.Writer, vs []internal.Var) error {
+	if len(vs) == 0 {
+		_, err := fmt.Fprintln(w, "No variables registered.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tDESCRIPTION")
+	for _, v := range vs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Name, v.Value, v.Description)
+	}
+	return tw.Flush()
+}
+
+func setVar(addr net.TCPAddr, params []string) error {
+	if len(params) != 2 {
+		return errors.New("want a variable name and a value")
+	}
+	out, err := request(addr, signal.SetVar, internal.Var{Name: params[0], Value: params[1]})
+	if err != nil {
+		return err
+	}
+	var change internal.VarChange
+	if err := json.Unmarshal(out, &change); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %s -> %s\n", params[0], change.Previous, change.Value)
+	return nil
+}
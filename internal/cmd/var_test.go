@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func TestPrintVars(t *testing.T) {
+	vs := []internal.Var{
+		{Name: "sampling-rate", Value: "10", Description: "Percentage of sampled requests."},
+		{Name: "tracing", Value: "false", Description: "Enables tracing."},
+	}
+	var buf bytes.Buffer
+	if err := printVars(&buf, vs); err != nil {
+		t.Fatal(err)
+	}
+	want := `NAME           VALUE  DESCRIPTION
+sampling-rate  10     Percentage of sampled requests.
+tracing        false  Enables tracing.
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/gops/signal"
+	"github.com/spf13/pflag"
+)
+
+var varsWatch time.Duration
+
+func varsFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&varsWatch, "watch", 0, "re-query at the given interval and print the values that changed")
+}
+
+func vars(addr net.TCPAddr, params []string) error {
+	if varsWatch > 0 {
+		return watchVars(addr, params, varsWatch)
+	}
+	out, err := request(addr, signal.Vars, params)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, out, "", "  "); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(os.Stdout)
+	return err
+}
+
+// flattenVars returns the compact JSON values of the variables vs, the
+// fields of objects being flattened into "var.field" keys.
+func flattenVars(vs []byte) (map[string]string, error) {
+	d := json.NewDecoder(bytes.NewReader(vs))
+	d.UseNumber()
+	var v map[string]interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	flat := make(map[string]string)
+	var flatten func(prefix string, v interface{})
+	flatten = func(prefix string, v interface{}) {
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			for k, v := range m {
+				flatten(prefix+"."+k, v)
+			}
+			return
+		}
+		b, _ := json.Marshal(v)
+		flat[prefix] = string(b)
+	}
+	for k, v := range v {
+		flatten(k, v)
+	}
+	return flat, nil
+}
+
+// printVarsDelta prints the values of vs that differ from prev, along with
+// the change of numbers.
+func printVarsDelta(w io.Writer, vs, prev map[string]string, now time.Time) {
+	var keys []string
+	for k, v := range vs {
+		if p, ok := prev[k]; !ok || p != v {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "=== %v\n", now.Format("15:04:05"))
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s: %s", k, vs[k])
+		if d, ok := numDelta(vs[k], prev[k]); ok {
+			fmt.Fprintf(w, " [%s]", d)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// numDelta returns the signed difference of the numbers n and prev.
+// Integers are subtracted exactly, as they may be nanosecond timestamps.
+func numDelta(n, prev string) (string, bool) {
+	i, err1 := strconv.ParseInt(n, 10, 64)
+	j, err2 := strconv.ParseInt(prev, 10, 64)
+	if err1 == nil && err2 == nil {
+		if i >= j {
+			return "+" + strconv.FormatInt(i-j, 10), true
+		}
+		return strconv.FormatInt(i-j, 10), true
+	}
+	f, err1 := strconv.ParseFloat(n, 64)
+	g, err2 := strconv.ParseFloat(prev, 64)
+	if err1 == nil && err2 == nil {
+		return signedNum(f - g), true
+	}
+	return "", false
+}
+
+func watchVars(addr net.TCPAddr, names []string, interval time.Duration) error {
+	var prev map[string]string
+	for {
+		out, err := request(addr, signal.Vars, names)
+		if err != nil {
+			return err
+		}
+		vs, err := flattenVars(out)
+		if err != nil {
+			return err
+		}
+		printVarsDelta(os.Stdout, vs, prev, time.Now())
+		prev = vs
+		time.Sleep(interval)
+	}
+}
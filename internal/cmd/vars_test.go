@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPrintVarsDelta(t *testing.T) {
+	prev, err := flattenVars([]byte(`{"requests": 10, "last": 1792347684915350286, "cache": {"hits": 5, "misses": 1}, "name": "a", "empty": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs, err := flattenVars([]byte(`{"requests": 12, "last": 1792347686946207662, "cache": {"hits": 5, "misses": 3.5}, "name": "b", "empty": {}, "new": [1, 2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	now := time.Date(2017, 1, 1, 15, 4, 7, 0, time.UTC)
+	printVarsDelta(&buf, vs, prev, now)
+	want := `=== 15:04:07
+cache.misses: 3.5 [+2.5]
+last: 1792347686946207662 [+2030857376]
+name: "b"
+new: [1,2]
+requests: 12 [+2]
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	printVarsDelta(&buf, vs, vs, now)
+	if got := buf.String(); got != "" {
+		t.Errorf("got output without changes:\n%s", got)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// Command is a command registered by the application with agent.Register,
+// as listed by signal.ListCommands.
+type Command struct {
+	Name        string
+	Description string
+}
+
+// CommandCall is a call of a registered command by signal.CallCommand.
+type CommandCall struct {
+	Name string
+	Args []string
+}
@@ -0,0 +1,10 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// CoverageOptions are the options of signal.Coverage.
+type CoverageOptions struct {
+	Reset bool // clear the counters once written
+}
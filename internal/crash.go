@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CrashDir returns the directory of the crash reports in gopsdir, one file
+// per process named by CrashFile.
+func CrashDir(gopsdir string) string {
+	return filepath.Join(gopsdir, "crashes")
+}
+
+// CrashFile returns the name of the crash report of the process with the
+// given PID that started at start, which is unique even if a later process
+// gets the same PID, as in containers.
+func CrashFile(pid int, start time.Time) string {
+	return fmt.Sprintf("%d-%d", pid, start.UnixNano())
+}
+
+// CrashHeader describes the process at the start of its crash report,
+// followed by an empty line and the crash output of the runtime, if any.
+type CrashHeader struct {
+	PID       int
+	Path      string
+	GoVersion string
+	Start     time.Time
+}
+
+// Write writes the header to w.
+func (h CrashHeader) Write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "pid: %d\npath: %s\ngo: %s\nstart: %s\n\n",
+		h.PID, h.Path, h.GoVersion, h.Start.Format(time.RFC3339Nano))
+	return err
+}
+
+// ParseCrashReport returns the header and the crash output of a crash
+// report.
+func ParseCrashReport(b []byte) (CrashHeader, []byte, error) {
+	var h CrashHeader
+	header, output, ok := bytes.Cut(b, []byte("\n\n"))
+	if !ok {
+		return h, nil, fmt.Errorf("crash report header is incomplete")
+	}
+	s := bufio.NewScanner(bytes.NewReader(header))
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), ": ")
+		if !ok {
+			continue
+		}
+		var err error
+		switch key {
+		case "pid":
+			h.PID, err = strconv.Atoi(value)
+		case "path":
+			h.Path = value
+		case "go":
+			h.GoVersion = value
+		case "start":
+			h.Start, err = time.Parse(time.RFC3339, value)
+		}
+		if err != nil {
+			return h, nil, fmt.Errorf("invalid crash report %s: %v", key, err)
+		}
+	}
+	return h, output, nil
+}
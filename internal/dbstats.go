@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// DBStats are the statistics of a database registered with
+// package agent/sqlstats, as returned by signal.DBStats.
+type DBStats struct {
+	Name  string
+	Stats DBPoolStats
+}
+
+// DBPoolStats are the fields of sql.DBStats, copied for the agent and the
+// CLI not to depend on database/sql.
+type DBPoolStats struct {
+	MaxOpenConnections int
+
+	OpenConnections int
+	InUse           int
+	Idle            int
+
+	WaitCount         int64
+	WaitDuration      time.Duration
+	MaxIdleClosed     int64
+	MaxIdleTimeClosed int64
+	MaxLifetimeClosed int64
+}
@@ -0,0 +1,15 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// HealthResult is the result of a health check registered with
+// agent.RegisterHealthCheck, as returned by signal.HealthCheck.
+type HealthResult struct {
+	Name    string
+	Latency time.Duration
+	Error   string // empty if the check passed
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// HistoryFile is a file captured by the agent in the background, such as
+// one of the profiles listed by signal.ListProfiles.
+type HistoryFile struct {
+	// Name identifies the file.
+	Name string
+
+	// Time is when the capture the file belongs to started.
+	Time time.Time
+
+	// Kind is the kind of the file, such as "cpu" or "heap".
+	Kind string
+
+	// Size is the size of the file in bytes.
+	Size int64
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "encoding/json"
+
+// The hooks below are set by the optional subpackages of the agent, when
+// they are imported, so that the agent itself doesn't depend on the
+// packages they need.
+
+// Expvars returns the values of the variables published with expvar that
+// have the given names, or of all of them if there are none. It is set by
+// package github.com/google/gops/agent/expvars.
+var Expvars func(names []string) (map[string]json.RawMessage, error)
+
+// DBStatsOf returns the statistics of the registered databases that have
+// the given names, or of all of them if there are none. It is set by
+// package github.com/google/gops/agent/sqlstats.
+var DBStatsOf func(names []string) ([]DBStats, error)
@@ -0,0 +1,23 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// LogLevelChange is a change of the log levels registered with
+// agent.RegisterLogLevel, as sent with signal.LogLevel.
+type LogLevelChange struct {
+	Logger string        // all the loggers if empty
+	Level  string        // unchanged if empty
+	For    time.Duration // permanent if zero
+}
+
+// LogLevel is the level of a logger, as returned by signal.LogLevel.
+type LogLevel struct {
+	Logger   string
+	Level    string
+	RevertTo string     `json:",omitempty"`
+	RevertAt *time.Time `json:",omitempty"`
+}
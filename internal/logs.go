@@ -0,0 +1,11 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// LogsOptions selects the log lines returned by signal.Logs.
+type LogsOptions struct {
+	Lines  int  // all the kept lines if not positive
+	Follow bool // keep sending new lines
+}
@@ -0,0 +1,22 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckName panics if name, of something of the given kind registered
+// with the agent such as "command", is empty, contains spaces or is
+// already a key of registered.
+func CheckName[V any](kind, name string, registered map[string]V) {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		panic(fmt.Sprintf("gops: invalid %s name %q", kind, name))
+	}
+	if _, ok := registered[name]; ok {
+		panic("gops: " + kind + " " + name + " registered twice")
+	}
+}
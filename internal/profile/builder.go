@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+// More field numbers of the messages of profile.proto.
+const (
+	profileSampleType    = 1
+	profileSample        = 2
+	profileTimeNanos     = 9
+	profileDurationNanos = 10
+	profilePeriodType    = 11
+	profilePeriod        = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+	sampleLabel      = 3
+
+	labelKey = 1
+	labelStr = 2
+)
+
+// ValueType is the type and unit of the values of samples, such as
+// "samples" and "count".
+type ValueType struct {
+	Type, Unit string
+}
+
+// Builder builds a profile of symbolized stacks, without addresses.
+type Builder struct {
+	s         symbolizer
+	fields    []field
+	locations map[internal.Frame]uint64 // ID of the locations
+}
+
+// NewBuilder returns a builder of a profile started at start, lasting d,
+// whose samples have values of the given types, sampled every period of
+// periodType.
+func NewBuilder(start time.Time, d time.Duration, types []ValueType, periodType ValueType, period int64) *Builder {
+	b := &Builder{
+		s:         symbolizer{strings: make(map[string]uint64), funcs: make(map[funcKey]uint64)},
+		locations: make(map[internal.Frame]uint64),
+	}
+	b.s.string("")
+	for _, t := range types {
+		b.fields = append(b.fields, bytesField(profileSampleType, b.valueType(t)))
+	}
+	b.fields = append(b.fields,
+		varint(profileTimeNanos, uint64(start.UnixNano())),
+		varint(profileDurationNanos, uint64(d)),
+		bytesField(profilePeriodType, b.valueType(periodType)),
+		varint(profilePeriod, uint64(period)),
+	)
+	return b
+}
+
+func (b *Builder) valueType(t ValueType) []byte {
+	return encode(nil, []field{
+		varint(valueTypeType, b.s.string(t.Type)),
+		varint(valueTypeUnit, b.s.string(t.Unit)),
+	})
+}
+
+// Add adds a sample of the stack of frames, innermost first, with the
+// given values, one per type, and string labels.
+func (b *Builder) Add(frames []internal.Frame, values []int64, labels map[string]string) {
+	var sample []field
+	for _, f := range frames {
+		sample = append(sample, varint(sampleLocationID, b.location(f)))
+	}
+	for _, v := range values {
+		sample = append(sample, varint(sampleValue, uint64(v)))
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		label := encode(nil, []field{
+			varint(labelKey, b.s.string(k)),
+			varint(labelStr, b.s.string(labels[k])),
+		})
+		sample = append(sample, bytesField(sampleLabel, label))
+	}
+	b.fields = append(b.fields, bytesField(profileSample, encode(nil, sample)))
+}
+
+// location returns the ID of the location of frame, adding it if needed.
+func (b *Builder) location(frame internal.Frame) uint64 {
+	if id, ok := b.locations[frame]; ok {
+		return id
+	}
+	id := uint64(len(b.locations) + 1)
+	b.locations[frame] = id
+	line := encode(nil, []field{
+		varint(lineFunctionID, b.s.function(frame)),
+		varint(lineLine, uint64(frame.Line)),
+	})
+	loc := encode(nil, []field{
+		varint(locationID, id),
+		bytesField(locationLine, line),
+	})
+	b.fields = append(b.fields, bytesField(profileLocation, loc))
+	return id
+}
+
+// Bytes returns the gzipped profile.
+func (b *Builder) Bytes() ([]byte, error) {
+	return compress(encode(nil, append(b.fields, b.s.newFields...)), true)
+}
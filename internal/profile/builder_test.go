@@ -0,0 +1,57 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/gops/internal"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(time.Unix(1, 0), time.Second, []ValueType{{"samples", "count"}}, ValueType{"wall", "nanoseconds"}, 100)
+	main := internal.Frame{Func: "main.main", File: "main.go", Line: 10}
+	b.Add([]internal.Frame{{Func: "main.a", File: "main.go", Line: 20}, main}, []int64{3}, map[string]string{"state": "select"})
+	b.Add([]internal.Frame{main}, []int64{1}, nil)
+	p, err := b.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int]int)
+	var strs []string
+	for _, f := range fields {
+		counts[f.num]++
+		if f.num == profileString {
+			strs = append(strs, string(f.b))
+		}
+	}
+	if counts[profileSample] != 2 || counts[profileLocation] != 2 || counts[profileFunction] != 2 {
+		t.Errorf("got %d samples, %d locations and %d functions; want 2 of each",
+			counts[profileSample], counts[profileLocation], counts[profileFunction])
+	}
+	if len(strs) == 0 || strs[0] != "" {
+		t.Errorf("got string table %q; want it to start with an empty string", strs)
+	}
+	// The symbolizer can read the profile back, with nothing to do.
+	if _, err := Symbolize(p, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package profile reads and writes the parts of pprof profiles that gops
+// needs, in the protocol buffer format described by
+// https://github.com/google/pprof/blob/main/proto/profile.proto.
+package profile
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Wire types of the protocol buffer encoding.
+const (
+	wireVarint = 0
+	wire64     = 1
+	wireBytes  = 2
+	wire32     = 5
+)
+
+// field is an encoded field of a message. Fields that aren't understood
+// are kept as is so that they can be encoded back.
+type field struct {
+	num  int
+	wire int
+	v    uint64 // for wireVarint, wire64 and wire32
+	b    []byte // for wireBytes
+}
+
+var errMalformed = errors.New("malformed profile")
+
+// decode splits the encoded message b into its fields.
+func decode(b []byte) ([]field, error) {
+	var fields []field
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errMalformed
+		}
+		b = b[n:]
+		f := field{num: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.v, n = binary.Uvarint(b); n <= 0 {
+				return nil, errMalformed
+			}
+			b = b[n:]
+		case wire64:
+			if len(b) < 8 {
+				return nil, errMalformed
+			}
+			f.v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wire32:
+			if len(b) < 4 {
+				return nil, errMalformed
+			}
+			f.v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return nil, errMalformed
+			}
+			f.b, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return nil, errMalformed
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// encode appends the encoded fields to b.
+func encode(b []byte, fields []field) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	for _, f := range fields {
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(f.num)<<3|uint64(f.wire))]...)
+		switch f.wire {
+		case wireVarint:
+			b = append(b, buf[:binary.PutUvarint(buf[:], f.v)]...)
+		case wire64:
+			binary.LittleEndian.PutUint64(buf[:], f.v)
+			b = append(b, buf[:8]...)
+		case wire32:
+			binary.LittleEndian.PutUint32(buf[:], uint32(f.v))
+			b = append(b, buf[:4]...)
+		case wireBytes:
+			b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(f.b)))]...)
+			b = append(b, f.b...)
+		}
+	}
+	return b
+}
+
+func varint(num int, v uint64) field {
+	return field{num: num, wire: wireVarint, v: v}
+}
+
+func bytesField(num int, b []byte) field {
+	return field{num: num, wire: wireBytes, b: b}
+}
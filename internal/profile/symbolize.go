@@ -0,0 +1,252 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/google/gops/internal"
+)
+
+// Field numbers of the messages of profile.proto.
+const (
+	profileMapping  = 3
+	profileLocation = 4
+	profileFunction = 5
+	profileString   = 6
+
+	mappingID              = 1
+	mappingHasFunctions    = 7
+	mappingHasFilenames    = 8
+	mappingHasLineNumbers  = 9
+	mappingHasInlineFrames = 10
+
+	locationID        = 1
+	locationMappingID = 2
+	locationAddress   = 3
+	locationLine      = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+)
+
+// Symbolize adds the function, file and line of the locations of the
+// profile p that have none. lookup is called once with the addresses of
+// these locations and returns their frames, innermost first, or nil for
+// the addresses it can't resolve. p may be gzipped, in which case the
+// returned profile is gzipped too.
+func Symbolize(p []byte, lookup func(addrs []uint64) ([][]internal.Frame, error)) ([]byte, error) {
+	orig := p
+	gzipped := len(p) > 2 && p[0] == 0x1f && p[1] == 0x8b
+	if gzipped {
+		zr, err := gzip.NewReader(bytes.NewReader(p))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+		p = raw
+	}
+	fields, err := decode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	s := symbolizer{strings: make(map[string]uint64), funcs: make(map[funcKey]uint64)}
+	var (
+		addrs []uint64
+		locs  []int // indexes in fields of the locations of addrs
+	)
+	for i, f := range fields {
+		switch f.num {
+		case profileString:
+			if _, ok := s.strings[string(f.b)]; !ok {
+				s.strings[string(f.b)] = s.numStrings
+			}
+			s.numStrings++
+		case profileFunction:
+			if err := s.addFunction(f.b); err != nil {
+				return nil, err
+			}
+		case profileLocation:
+			loc, err := decode(f.b)
+			if err != nil {
+				return nil, err
+			}
+			var addr uint64
+			hasLines := false
+			for _, lf := range loc {
+				switch lf.num {
+				case locationAddress:
+					addr = lf.v
+				case locationLine:
+					hasLines = true
+				}
+			}
+			if !hasLines && addr != 0 {
+				addrs = append(addrs, addr)
+				locs = append(locs, i)
+			}
+		}
+	}
+	if len(addrs) == 0 {
+		return orig, nil
+	}
+	frames, err := lookup(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make(map[uint64]bool)
+	for i, loc := range locs {
+		if i >= len(frames) || len(frames[i]) == 0 {
+			continue
+		}
+		lfs, _ := decode(fields[loc].b)
+		for _, frame := range frames[i] {
+			line := []field{
+				varint(lineFunctionID, s.function(frame)),
+				varint(lineLine, uint64(frame.Line)),
+			}
+			lfs = append(lfs, bytesField(locationLine, encode(nil, line)))
+		}
+		for _, lf := range lfs {
+			if lf.num == locationMappingID {
+				mappings[lf.v] = true
+			}
+		}
+		fields[loc].b = encode(nil, lfs)
+	}
+	for i, f := range fields {
+		if f.num != profileMapping {
+			continue
+		}
+		if fields[i].b, err = markSymbolized(f.b, mappings); err != nil {
+			return nil, err
+		}
+	}
+	fields = append(fields, s.newFields...)
+	return compress(encode(nil, fields), gzipped)
+}
+
+// symbolizer keeps track of the strings and functions of a profile.
+type symbolizer struct {
+	strings    map[string]uint64 // index in the string table
+	numStrings uint64
+	funcs      map[funcKey]uint64 // ID of the functions
+	maxFuncID  uint64
+	newFields  []field // strings and functions to add to the profile
+}
+
+func (s *symbolizer) addFunction(b []byte) error {
+	fs, err := decode(b)
+	if err != nil {
+		return err
+	}
+	var id, name, file uint64
+	for _, f := range fs {
+		switch f.num {
+		case functionID:
+			id = f.v
+		case functionName:
+			name = f.v
+		case functionFilename:
+			file = f.v
+		}
+	}
+	if id > s.maxFuncID {
+		s.maxFuncID = id
+	}
+	s.funcs[funcKey{name, file}] = id
+	return nil
+}
+
+// funcKey identifies a function by the indexes of its name and file in the
+// string table.
+type funcKey struct {
+	name, file uint64
+}
+
+// string returns the index of str in the string table, adding it if needed.
+func (s *symbolizer) string(str string) uint64 {
+	if i, ok := s.strings[str]; ok {
+		return i
+	}
+	i := s.numStrings
+	s.strings[str] = i
+	s.numStrings++
+	s.newFields = append(s.newFields, bytesField(profileString, []byte(str)))
+	return i
+}
+
+// function returns the ID of the function of frame, adding it if needed.
+func (s *symbolizer) function(frame internal.Frame) uint64 {
+	name, file := s.string(frame.Func), s.string(frame.File)
+	key := funcKey{name, file}
+	if id, ok := s.funcs[key]; ok {
+		return id
+	}
+	s.maxFuncID++
+	id := s.maxFuncID
+	s.funcs[key] = id
+	fn := []field{
+		varint(functionID, id),
+		varint(functionName, name),
+		varint(functionSystemName, name),
+		varint(functionFilename, file),
+	}
+	s.newFields = append(s.newFields, bytesField(profileFunction, encode(nil, fn)))
+	return id
+}
+
+// markSymbolized marks the mapping b as symbolized if its ID is in ids.
+func markSymbolized(b []byte, ids map[uint64]bool) ([]byte, error) {
+	fs, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	var out []field
+	for _, f := range fs {
+		if f.num == mappingID && !ids[f.v] {
+			return b, nil
+		}
+		switch f.num {
+		case mappingHasFunctions, mappingHasFilenames, mappingHasLineNumbers, mappingHasInlineFrames:
+		default:
+			out = append(out, f)
+		}
+	}
+	out = append(out,
+		varint(mappingHasFunctions, 1),
+		varint(mappingHasFilenames, 1),
+		varint(mappingHasLineNumbers, 1),
+		varint(mappingHasInlineFrames, 1),
+	)
+	return encode(nil, out), nil
+}
+
+func compress(p []byte, gzipped bool) ([]byte, error) {
+	if !gzipped {
+		return p, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(p); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
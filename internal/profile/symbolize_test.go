@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package profile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/gops/internal"
+)
+
+func message(fields ...field) []byte {
+	return encode(nil, fields)
+}
+
+func TestSymbolize(t *testing.T) {
+	p := message(
+		bytesField(profileString, nil),
+		bytesField(profileString, []byte("main.known")),
+		bytesField(profileString, []byte("main.go")),
+		bytesField(profileMapping, message(varint(mappingID, 1))),
+		bytesField(profileFunction, message(varint(functionID, 7), varint(functionName, 1), varint(functionFilename, 2))),
+		// Already symbolized.
+		bytesField(profileLocation, message(varint(locationID, 1), varint(locationMappingID, 1), varint(locationAddress, 0x100),
+			bytesField(locationLine, message(varint(lineFunctionID, 7), varint(lineLine, 3))))),
+		bytesField(profileLocation, message(varint(locationID, 2), varint(locationMappingID, 1), varint(locationAddress, 0x200))),
+		bytesField(profileLocation, message(varint(locationID, 3), varint(locationMappingID, 1), varint(locationAddress, 0x300))),
+	)
+	var lookedUp []uint64
+	out, err := Symbolize(p, func(addrs []uint64) ([][]internal.Frame, error) {
+		lookedUp = addrs
+		return [][]internal.Frame{
+			{{Func: "main.inlined", File: "main.go", Line: 10}, {Func: "main.known", File: "main.go", Line: 20}},
+			nil,
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0x200, 0x300}; !reflect.DeepEqual(lookedUp, want) {
+		t.Errorf("looked up %#x; want %#x", lookedUp, want)
+	}
+
+	fields, err := decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		strs  []string
+		funcs = make(map[uint64]string) // name by ID
+		lines = make(map[uint64][]string)
+	)
+	for _, f := range fields {
+		switch f.num {
+		case profileString:
+			strs = append(strs, string(f.b))
+		case profileMapping:
+			fs, _ := decode(f.b)
+			if len(fs) != 5 {
+				t.Errorf("mapping has %d fields; want it marked symbolized", len(fs))
+			}
+		}
+	}
+	for _, f := range fields {
+		if f.num != profileFunction {
+			continue
+		}
+		fs, _ := decode(f.b)
+		var id, name uint64
+		for _, ff := range fs {
+			switch ff.num {
+			case functionID:
+				id = ff.v
+			case functionName:
+				name = ff.v
+			}
+		}
+		funcs[id] = strs[name]
+	}
+	for _, f := range fields {
+		if f.num != profileLocation {
+			continue
+		}
+		fs, _ := decode(f.b)
+		var id uint64
+		for _, lf := range fs {
+			switch lf.num {
+			case locationID:
+				id = lf.v
+			case locationLine:
+				line, _ := decode(lf.b)
+				lines[id] = append(lines[id], funcs[line[0].v])
+			}
+		}
+	}
+	want := map[uint64][]string{
+		1: {"main.known"},
+		2: {"main.inlined", "main.known"},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got functions by location %v; want %v", lines, want)
+	}
+	if len(funcs) != 2 {
+		t.Errorf("got functions %v; want main.known to be reused", funcs)
+	}
+}
+
+func TestSymbolizeNothing(t *testing.T) {
+	p := message(bytesField(profileString, nil))
+	out, err := Symbolize(p, func([]uint64) ([][]internal.Frame, error) {
+		t.Fatal("unexpected lookup")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(p) {
+		t.Errorf("profile changed")
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// Commands added after signal.SetGCPercent prefix their reply with one of
+// the following status bytes. An empty reply means that the agent doesn't
+// know the command.
+const (
+	// StatusOK is followed by the output of the command.
+	StatusOK = byte(0x0)
+
+	// StatusError is followed by the error message.
+	StatusError = byte(0x1)
+)
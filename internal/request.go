@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// Request is a request being served, tracked with agent.TrackRequest, as
+// returned by signal.Requests.
+type Request struct {
+	Method    string
+	Path      string
+	Remote    string
+	Start     time.Time
+	Age       time.Duration
+	Goroutine int64 // ID of the goroutine serving the request
+}
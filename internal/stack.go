@@ -0,0 +1,76 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// StackOptions selects the goroutines written by signal.FilteredStackTrace.
+// Zero fields match every goroutine.
+type StackOptions struct {
+	// State is the state of the goroutine, such as "chan receive".
+	State string
+
+	// Func is a regular expression matched against the function of
+	// every frame of the goroutine, including the creator.
+	Func string
+
+	// MinWait is the minimum time the goroutine has been blocked. The
+	// runtime reports blocking times in minutes only.
+	MinWait time.Duration
+
+	// IDs are the IDs of the goroutines.
+	IDs []int64
+
+	// Limit is the maximum number of goroutines to write.
+	Limit int
+
+	// Labels are pprof labels that the goroutine must have, as
+	// "key=value", or "key" for any value.
+	Labels []string
+
+	// GroupBy is a pprof label key. If set, the number of matching
+	// goroutines is written for each value of the label instead of their
+	// stacks.
+	GroupBy string
+
+	// PerGoroutine requires the goroutines to be written one by one, as
+	// in debug=2 dumps. Otherwise, when the runtime doesn't print labels
+	// in tracebacks, the goroutines selected by label are written
+	// aggregated by stack, as in debug=1 dumps.
+	PerGoroutine bool
+}
+
+// Filtered reports whether o filters any goroutine.
+func (o StackOptions) Filtered() bool {
+	return o.State != "" || o.Func != "" || o.MinWait > 0 || len(o.IDs) > 0 || o.Limit > 0 ||
+		len(o.Labels) > 0 || o.GroupBy != ""
+}
+
+// NeedLabels reports whether o selects or groups goroutines by label.
+func (o StackOptions) NeedLabels() bool {
+	return len(o.Labels) > 0 || o.GroupBy != ""
+}
+
+// SampleOptions configures signal.SampleStacks.
+type SampleOptions struct {
+	// StackOptions selects the sampled goroutines. Limit is the maximum
+	// number of stacks in the text report, and GroupBy is not supported.
+	StackOptions
+
+	// Duration is how long the stacks are sampled for.
+	Duration time.Duration
+
+	// Interval is the time between two samples. Defaults to 100ms.
+	Interval time.Duration
+}
+
+// StackSample is the result of signal.SampleStacks.
+type StackSample struct {
+	// Text reports the most frequent stacks.
+	Text string
+
+	// Profile is the gzipped pprof profile of the stacks.
+	Profile []byte
+}
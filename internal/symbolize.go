@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// Frame is a function call resolved by signal.Symbolize from a program
+// counter.
+type Frame struct {
+	// Func is the package path-qualified function name.
+	Func string
+
+	// File and Line are the position of the program counter.
+	File string
+	Line int
+}
@@ -0,0 +1,20 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+// Var is a variable registered by the application with agent.RegisterVar,
+// as returned by signal.GetVars and changed by signal.SetVar.
+type Var struct {
+	Name        string
+	Value       string
+	Description string `json:",omitempty"`
+}
+
+// VarChange is a change of a variable, as returned by signal.SetVar. The
+// values are those returned by the String method of the variable.
+type VarChange struct {
+	Previous string
+	Value    string
+}
@@ -17,6 +17,7 @@ func main() {
 	var root = cmd.NewRoot()
 	root.AddCommand(cmd.ProcessCommand())
 	root.AddCommand(cmd.TreeCommand())
+	root.AddCommand(cmd.CrashesCommand())
 	root.AddCommand(cmd.AgentCommands()...)
 
 	// Legacy support for `gops <pid>` command.
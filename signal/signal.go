@@ -24,7 +24,8 @@ const (
 	// CPUProfile starts `go tool pprof` with the current CPU profile
 	CPUProfile = byte(0x6)
 
-	// Stats returns Go runtime statistics such as number of goroutines, GOMAXPROCS, and NumCPU.
+	// Stats returns Go runtime statistics such as number of goroutines, GOMAXPROCS, and NumCPU,
+	// along with goroutine counts by state, GC settings, uptime and cgroup limits.
 	Stats = byte(0x7)
 
 	// Trace starts the Go execution tracer, waits 5 seconds and launches the trace tool.
@@ -35,4 +36,106 @@ const (
 
 	// SetGCPercent sets the garbage collection target percentage.
 	SetGCPercent = byte(0x10)
+
+	// FilteredStackTrace prints the stack trace of the goroutines matching
+	// the JSON encoded options that follow the command.
+	FilteredStackTrace = byte(0x11)
+
+	// TraceSnapshot writes the execution trace kept by the flight recorder.
+	TraceSnapshot = byte(0x12)
+
+	// ListProfiles lists the profiles captured in the background.
+	ListProfiles = byte(0x13)
+
+	// FetchProfile returns a profile captured in the background, named by
+	// the JSON encoded string that follows the command.
+	FetchProfile = byte(0x14)
+
+	// ListCaptures lists the diagnostics captured when crossing thresholds.
+	ListCaptures = byte(0x15)
+
+	// FetchCapture returns a file of diagnostics captured when crossing
+	// thresholds, named by the JSON encoded string that follows the command.
+	FetchCapture = byte(0x16)
+
+	// GCTrace streams a line per completed garbage collection until the
+	// client closes the connection.
+	GCTrace = byte(0x17)
+
+	// GCStats returns the JSON encoded garbage collection statistics of
+	// debug.ReadGCStats, with percentiles of the pause history.
+	GCStats = byte(0x18)
+
+	// HeapDump writes a heap dump, as written by debug.WriteHeapDump.
+	HeapDump = byte(0x19)
+
+	// FileDescriptors lists the open file descriptors and sockets.
+	FileDescriptors = byte(0x1a)
+
+	// Symbolize returns the JSON encoded frames, innermost first, of each
+	// program counter of the JSON encoded list that follows the command.
+	// Program counters are instruction addresses, as in pprof profiles.
+	Symbolize = byte(0x1b)
+
+	// Vars returns the JSON encoded expvar variables named by the JSON
+	// encoded list that follows the command, or all of them if empty.
+	Vars = byte(0x1c)
+
+	// SampleStacks samples the goroutine stacks with the JSON encoded
+	// options that follow the command, and returns the JSON encoded
+	// aggregated stacks as text and as a pprof profile.
+	SampleStacks = byte(0x1d)
+
+	// ListCommands returns the JSON encoded list of commands registered by
+	// the application.
+	ListCommands = byte(0x1e)
+
+	// CallCommand runs the registered command named by the JSON encoded
+	// call that follows the command, and returns its output.
+	CallCommand = byte(0x1f)
+
+	// Capabilities returns the JSON encoded version of the agent and of
+	// Go, the commands the agent supports and the options it was started
+	// with.
+	Capabilities = byte(0x20)
+
+	// HealthCheck runs the health checks registered by the application and
+	// named by the JSON encoded list that follows the command, or all of
+	// them if empty, and returns their JSON encoded results.
+	HealthCheck = byte(0x21)
+
+	// GetVars returns the JSON encoded variables registered by the
+	// application and named by the JSON encoded list that follows the
+	// command, or all of them if empty.
+	GetVars = byte(0x22)
+
+	// SetVar sets the variable registered by the application to the JSON
+	// encoded value that follows the command, and returns the JSON encoded
+	// previous value.
+	SetVar = byte(0x23)
+
+	// LogLevel applies the JSON encoded change of the log levels registered
+	// by the application that follows the command, and returns the JSON
+	// encoded levels.
+	LogLevel = byte(0x24)
+
+	// Logs returns the last log lines kept by the agent, selected by the
+	// JSON encoded options that follow the command, and then the new lines
+	// as they are written if following.
+	Logs = byte(0x25)
+
+	// Requests returns the JSON encoded HTTP requests being served by the
+	// handlers wrapped by the agent, oldest first.
+	Requests = byte(0x26)
+
+	// DBStats returns the JSON encoded connection pool statistics of the
+	// databases registered by the application and named by the JSON
+	// encoded list that follows the command, or all of them if empty.
+	DBStats = byte(0x27)
+
+	// Coverage returns a tar archive of the coverage meta-data and counter
+	// files of a program built with -cover, as written by the
+	// runtime/coverage package, with the JSON encoded options that follow
+	// the command.
+	Coverage = byte(0x28)
 )